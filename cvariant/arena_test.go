@@ -0,0 +1,21 @@
+package cvariant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArena(t *testing.T) {
+	var a Arena
+	v := a.NewKeyValueList(1)
+	list := v.KeyValueList()
+	list[0] = KeyValue{Key: a.CopyString("key"), Value: a.NewValueList(2)}
+	list[0].Value.ValueList()[1] = NewString(a.CopyString("value"))
+	assert.EqualValues(t, `{"key":[,"value"]}`, v.String())
+
+	a.Reset()
+	v = a.NewValueList(2)
+	assert.EqualValues(t, []Variant{{}, {}}, v.ValueList())
+	assert.Panics(t, func() { a.NewValueList(-1) })
+}
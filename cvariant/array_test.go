@@ -0,0 +1,57 @@
+package cvariant
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArrays(t *testing.T) {
+	ints := NewInt64Array([]int64{1, math.MinInt64})
+	assert.EqualValues(t, TypeInt64Array, ints.Type())
+	assert.EqualValues(t, []int64{1, math.MinInt64}, ints.Int64Array())
+	assert.EqualValues(t, "int64[1,-9223372036854775808]", ints.String())
+
+	floats := NewFloat64Array(make([]float64, 1, 3))
+	assert.EqualValues(t, TypeFloat64Array, floats.Type())
+	floats.Resize(3)
+	assert.EqualValues(t, []float64{0, 0, 0}, floats.Float64Array())
+
+	strs := NewStringArray([]string{"a", "long string value"})
+	assert.EqualValues(t, TypeStringArray, strs.Type())
+	assert.EqualValues(t, []string{"a", "long string value"}, strs.StringArray())
+	assert.EqualValues(t, `cvariant.NewStringArray([]string{"a", "long string value"})`, strs.GoString())
+
+	for _, v := range []Variant{ints, floats, strs} {
+		assert.NoError(t, v.Validate())
+	}
+	assert.PanicsWithError(t, "Variant type is StringArray, want Int64Array", func() { strs.Int64Array() })
+}
+
+func TestArrayCapOverflow(t *testing.T) {
+	// Allocating an array with a capacity that exceeds MaxSliceCap is too expensive
+	// for a test, so create the Variant from a slice header directly.
+	arr := []int64{1, 2, 3}
+	v := newSlice(int64ArrayData(arr), 3, MaxSliceCap+1, TypeInt64Array)
+	assert.EqualValues(t, 3, len(v.Int64Array()))
+	assert.EqualValues(t, 3, cap(v.Int64Array()))
+}
+
+func TestArrayConversion(t *testing.T) {
+	list := NewValueList([]Variant{NewInt(1), NewFloat64(2.5)})
+	v, err := list.ToArray(TypeFloat64Array)
+	require.NoError(t, err)
+	assert.EqualValues(t, []float64{1, 2.5}, v.Float64Array())
+
+	_, err = list.ToArray(TypeInt64Array)
+	assert.EqualError(t, err, "cannot convert ValueList to Int64Array: element 1 is Float64")
+
+	l := v.ToValueList()
+	assert.EqualValues(t, "[1,2.5]", l.String())
+
+	p, err := Parse(`string["a",` + "`b`]")
+	require.NoError(t, err)
+	assert.EqualValues(t, []string{"a", "b"}, p.StringArray())
+}
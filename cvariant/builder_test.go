@@ -0,0 +1,23 @@
+package cvariant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder(t *testing.T) {
+	var b Builder
+	b.BeginList()
+	b.String("a long string value")
+	b.BeginMap()
+	b.Key("k")
+	b.Float64(1.5)
+	b.End()
+	b.End()
+	v := b.Result()
+	assert.EqualValues(t, `["a long string value",{"k":1.5}]`, v.String())
+	assert.NoError(t, v.Validate())
+
+	assert.PanicsWithError(t, "invalid Builder.End call: no list is open", func() { b.End() })
+}
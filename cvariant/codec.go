@@ -0,0 +1,113 @@
+package cvariant
+
+import (
+	"database/sql"
+	"database/sql/driver"
+
+	"github.com/tigrannajaryan/govariant/variant"
+)
+
+// The functions in this file convert the Variant to or from variant.Variant and use
+// the encoding implemented by the variant package. The encoded data is the same for
+// both packages, i.e. data encoded by one package can be decoded by the other.
+
+// MarshalJSON implements json.Marshaler interface. See variant.Variant.MarshalJSON
+// for the description of the encoding.
+func (v Variant) MarshalJSON() ([]byte, error) {
+	return ToVariant(v).MarshalJSON()
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface. See variant.Variant.UnmarshalJSON
+// for the description of the decoding.
+func (v *Variant) UnmarshalJSON(data []byte) error {
+	var r variant.Variant
+	if err := r.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	*v = FromVariant(r)
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler interface. See
+// variant.Variant.MarshalBinary for the description of the encoding.
+func (v Variant) MarshalBinary() ([]byte, error) {
+	return ToVariant(v).MarshalBinary()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler interface. See
+// variant.Variant.UnmarshalBinary for the description of the decoding.
+func (v *Variant) UnmarshalBinary(data []byte) error {
+	var r variant.Variant
+	if err := r.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	*v = FromVariant(r)
+	return nil
+}
+
+// Value implements driver.Valuer interface. See variant.Variant.Value for
+// the description of how the values are stored.
+func (v Variant) Value() (driver.Value, error) {
+	return ToVariant(v).Value()
+}
+
+// BinaryValuer returns a driver.Valuer that stores TypeValueList, TypeKeyValueList and
+// the array types in compact binary encoding. See variant.Variant.BinaryValuer.
+func (v Variant) BinaryValuer() driver.Valuer {
+	return ToVariant(v).BinaryValuer()
+}
+
+// Scan implements sql.Scanner interface. See variant.Variant.Scan for the description
+// of how the values are scanned.
+func (v *Variant) Scan(src interface{}) error {
+	var r variant.Variant
+	if err := r.Scan(src); err != nil {
+		return err
+	}
+	*v = FromVariant(r)
+	return nil
+}
+
+// JSONScanner returns a sql.Scanner that decodes text and binary values as JSON and
+// stores the result in v. See variant.Variant.JSONScanner.
+func (v *Variant) JSONScanner() sql.Scanner {
+	return (*jsonScanner)(v)
+}
+
+type jsonScanner Variant
+
+func (s *jsonScanner) Scan(src interface{}) error {
+	var r variant.Variant
+	if err := r.JSONScanner().Scan(src); err != nil {
+		return err
+	}
+	*s = jsonScanner(FromVariant(r))
+	return nil
+}
+
+// BinaryScanner returns a sql.Scanner that decodes binary values that are encoded by
+// MarshalBinary and stores the result in v. See variant.Variant.BinaryScanner.
+func (v *Variant) BinaryScanner() sql.Scanner {
+	return (*binaryScanner)(v)
+}
+
+type binaryScanner Variant
+
+func (s *binaryScanner) Scan(src interface{}) error {
+	var r variant.Variant
+	if err := r.BinaryScanner().Scan(src); err != nil {
+		return err
+	}
+	*s = binaryScanner(FromVariant(r))
+	return nil
+}
+
+// ScanRows reads all remaining rows and returns them as a TypeValueList of
+// TypeKeyValueList elements. See variant.ScanRows.
+func ScanRows(rows *sql.Rows) (Variant, error) {
+	r, err := variant.ScanRows(rows)
+	if err != nil {
+		return Variant{}, err
+	}
+	return FromVariant(r), nil
+}
@@ -0,0 +1,87 @@
+package cvariant
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tigrannajaryan/govariant/variant"
+)
+
+func TestJSON(t *testing.T) {
+	v := FromVariant(createConvertTestValue())
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	// The encoding is the same as for variant.Variant.
+	expected, err := json.Marshal(createConvertTestValue())
+	require.NoError(t, err)
+	assert.EqualValues(t, string(expected), string(b))
+
+	// Bytes are decoded as base64 strings, so compare the encoded values.
+	var r Variant
+	require.NoError(t, json.Unmarshal(b, &r))
+	b2, err := json.Marshal(r)
+	require.NoError(t, err)
+	assert.EqualValues(t, string(b), string(b2))
+
+	assert.Error(t, r.UnmarshalJSON([]byte("[1,")))
+}
+
+func TestBinary(t *testing.T) {
+	v := FromVariant(createConvertTestValue())
+	b, err := v.MarshalBinary()
+	require.NoError(t, err)
+
+	// Data encoded by one package can be decoded by the other.
+	var src variant.Variant
+	require.NoError(t, src.UnmarshalBinary(b))
+	assert.EqualValues(t, createConvertTestValue().String(), src.String())
+
+	var r Variant
+	require.NoError(t, r.UnmarshalBinary(b))
+	assert.EqualValues(t, v.String(), r.String())
+
+	assert.Error(t, r.UnmarshalBinary(b[:len(b)-1]))
+}
+
+func TestValueAndScan(t *testing.T) {
+	tests := []struct {
+		v     Variant
+		value interface{}
+	}{
+		{NewEmpty(), nil},
+		{NewInt(12), int64(12)},
+		{NewFloat64(1.5), 1.5},
+		{NewString("abc"), "abc"},
+		{NewBytes([]byte{1}), []byte{1}},
+	}
+	for _, test := range tests {
+		value, err := test.v.Value()
+		require.NoError(t, err)
+		assert.EqualValues(t, test.value, value)
+
+		var r Variant
+		require.NoError(t, r.Scan(value))
+		assert.EqualValues(t, test.v.String(), r.String())
+	}
+
+	v := NewKeyValueList([]KeyValue{{Key: "a", Value: NewInt(1)}})
+	value, err := v.Value()
+	require.NoError(t, err)
+	var r Variant
+	require.NoError(t, r.JSONScanner().Scan(value))
+	assert.EqualValues(t, v.String(), r.String())
+	assert.Error(t, r.JSONScanner().Scan("abc"))
+
+	value, err = v.BinaryValuer().Value()
+	require.NoError(t, err)
+	r = Variant{}
+	require.NoError(t, r.BinaryScanner().Scan(value))
+	assert.EqualValues(t, v.String(), r.String())
+	assert.Error(t, r.BinaryScanner().Scan([]byte{1}))
+
+	assert.Error(t, r.Scan(struct{}{}))
+}
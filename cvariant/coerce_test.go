@@ -0,0 +1,65 @@
+package cvariant
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoercer(t *testing.T) {
+	v := NewString("1e3")
+	i, err := v.AsInt()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1000, i)
+
+	f, err := v.AsFloat64()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1000, f)
+
+	s, err := v.AsString()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "1e3", s)
+
+	_, err = Coercer{Strict: true}.AsInt(v)
+	assert.Error(t, err)
+
+	v = NewFloat64(2.5)
+	_, err = v.AsInt()
+	assert.Error(t, err)
+	i, err = Coercer{Truncate: true}.AsInt(v)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, i)
+
+	v = NewFloat64(math.NaN())
+	_, err = v.AsInt()
+	assert.Error(t, err)
+
+	v = NewInt(-3)
+	f, err = v.AsFloat64()
+	assert.NoError(t, err)
+	assert.EqualValues(t, -3, f)
+
+	s, err = v.AsString()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "-3", s)
+
+	b, err := v.AsBool()
+	assert.NoError(t, err)
+	assert.True(t, b)
+	_, err = Coercer{Strict: true}.AsBool(v)
+	assert.Error(t, err)
+
+	v = NewString("false")
+	b, err = v.AsBool()
+	assert.NoError(t, err)
+	assert.False(t, b)
+
+	v = NewBytes([]byte{1})
+	_, err = v.AsInt()
+	assert.Error(t, err)
+	_, err = v.AsFloat64()
+	assert.Error(t, err)
+	_, err = v.AsBool()
+	assert.Error(t, err)
+}
@@ -0,0 +1,87 @@
+package cvariant
+
+import "github.com/tigrannajaryan/govariant/variant"
+
+// FromVariant converts a variant.Variant to a Variant.
+//
+// Strings, byte slices and arrays are not copied, the returned Variant shares their
+// storage with v. Lists are converted recursively, which allocates new lists. The
+// capacity of byte slices and arrays is trimmed to the length if it exceeds
+// MaxSliceCap.
+func FromVariant(v variant.Variant) Variant {
+	switch v.Type() {
+	case variant.TypeEmpty:
+		return NewEmpty()
+	case variant.TypeInt:
+		return NewInt(v.IntVal())
+	case variant.TypeFloat64:
+		return NewFloat64(v.Float64Val())
+	case variant.TypeString:
+		return NewString(v.StringVal())
+	case variant.TypeBytes:
+		return NewBytes(v.Bytes())
+	case variant.TypeValueList:
+		src := v.ValueList()
+		list := make([]Variant, len(src))
+		for i := range src {
+			list[i] = FromVariant(src[i])
+		}
+		return NewValueList(list)
+	case variant.TypeKeyValueList:
+		src := v.KeyValueList()
+		list := make([]KeyValue, len(src))
+		for i := range src {
+			list[i] = KeyValue{Key: src[i].Key, Value: FromVariant(src[i].Value)}
+		}
+		return NewKeyValueList(list)
+	case variant.TypeInt64Array:
+		return NewInt64Array(v.Int64Array())
+	case variant.TypeFloat64Array:
+		return NewFloat64Array(v.Float64Array())
+	case variant.TypeStringArray:
+		return NewStringArray(v.StringArray())
+	}
+	panic(&variant.InvalidTypeError{Type: v.Type()})
+}
+
+// ToVariant converts a Variant to a variant.Variant.
+//
+// Strings, byte slices and arrays are not copied, the returned variant.Variant shares
+// their storage with v. Lists are converted recursively, which allocates new lists.
+// Will panic if the length of a string or a slice exceeds the maximum length supported
+// by the "variant" package, which is possible on 32 bit systems only.
+func ToVariant(v Variant) variant.Variant {
+	switch v.Type() {
+	case TypeEmpty:
+		return variant.NewEmpty()
+	case TypeInt:
+		return variant.NewInt(v.IntVal())
+	case TypeFloat64:
+		return variant.NewFloat64(v.Float64Val())
+	case TypeString:
+		return variant.NewString(v.StringVal())
+	case TypeBytes:
+		return variant.NewBytes(v.Bytes())
+	case TypeValueList:
+		src := v.ValueList()
+		list := make([]variant.Variant, len(src))
+		for i := range src {
+			list[i] = ToVariant(src[i])
+		}
+		return variant.NewValueList(list)
+	case TypeKeyValueList:
+		src := v.KeyValueList()
+		list := make([]variant.KeyValue, len(src))
+		for i := range src {
+			list[i] = variant.KeyValue{Key: src[i].Key, Value: ToVariant(src[i].Value)}
+		}
+		return variant.NewKeyValueList(list)
+	case TypeInt64Array:
+		return variant.NewInt64Array(v.Int64Array())
+	case TypeFloat64Array:
+		return variant.NewFloat64Array(v.Float64Array())
+	case TypeStringArray:
+		return variant.NewStringArray(v.StringArray())
+	}
+	panic(&InvalidTypeError{Type: v.Type()})
+}
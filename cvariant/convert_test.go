@@ -0,0 +1,151 @@
+package cvariant
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tigrannajaryan/govariant/variant"
+)
+
+const maxInt = int(^uint(0) >> 1)
+const minInt = -maxInt - 1
+
+func TestIntAndFloatRange(t *testing.T) {
+	for _, i := range []int{0, 1, -1, maxInt, minInt} {
+		v := NewInt(i)
+		assert.EqualValues(t, TypeInt, v.Type())
+		assert.EqualValues(t, i, v.IntVal())
+	}
+	for _, f := range []float64{0, -1.5, math.MaxFloat64, math.SmallestNonzeroFloat64, math.Inf(-1)} {
+		v := NewFloat64(f)
+		assert.EqualValues(t, TypeFloat64, v.Type())
+		assert.EqualValues(t, f, v.Float64Val())
+	}
+	v := NewFloat64(math.NaN())
+	assert.True(t, math.IsNaN(v.Float64Val()))
+}
+
+func TestSliceLenAndCap(t *testing.T) {
+	b := make([]byte, 3, 10)
+	v := NewBytes(b)
+	assert.EqualValues(t, 3, v.Len())
+	assert.EqualValues(t, 10, cap(v.Bytes()))
+	v.Resize(10)
+	assert.EqualValues(t, 10, len(v.Bytes()))
+	assert.Panics(t, func() { v.Resize(11) })
+}
+
+// The slices below are created using newSlice, since allocating a slice with
+// a capacity that exceeds MaxSliceCap is too expensive for a test.
+func TestSliceCapOverflow(t *testing.T) {
+	b := []byte("abc")
+
+	v := newSlice(bytesData(b), 3, MaxSliceCap, TypeBytes)
+	n, c := v.sliceLenCap()
+	assert.EqualValues(t, 3, n)
+	assert.EqualValues(t, MaxSliceCap, c)
+
+	// The capacity is trimmed to the length.
+	v = newSlice(bytesData(b), 3, MaxSliceCap+1, TypeBytes)
+	assert.EqualValues(t, TypeBytes, v.Type())
+	assert.EqualValues(t, 3, v.Len())
+	assert.EqualValues(t, b, v.Bytes())
+	assert.EqualValues(t, 3, cap(v.Bytes()))
+	assert.Panics(t, func() { v.Resize(4) })
+
+	// Resize reduces the trimmed capacity.
+	v.Resize(2)
+	assert.EqualValues(t, 2, v.Len())
+	assert.EqualValues(t, b[:2:2], v.Bytes())
+	assert.Panics(t, func() { v.Resize(3) })
+
+	// Lengths that do not fit in the capacity bits. The pointer is not nil to keep
+	// the Variants valid, the elements are not accessed.
+	v = newSlice(bytesData(b), MaxSliceLen, MaxSliceLen, TypeValueList)
+	assert.EqualValues(t, TypeValueList, v.Type())
+	n, c = v.sliceLenCap()
+	assert.EqualValues(t, MaxSliceLen, v.Len())
+	assert.EqualValues(t, MaxSliceLen, n)
+	assert.EqualValues(t, MaxSliceLen, c)
+
+	v = newSlice(bytesData(b), MaxSliceCap+1, MaxSliceCap+1, TypeKeyValueList)
+	assert.EqualValues(t, TypeKeyValueList, v.Type())
+	assert.EqualValues(t, MaxSliceCap+1, v.Len())
+	v.Resize(MaxSliceCap)
+	n, c = v.sliceLenCap()
+	assert.EqualValues(t, MaxSliceCap, n)
+	assert.EqualValues(t, MaxSliceCap, c)
+}
+
+func createConvertTestValue() variant.Variant {
+	return variant.NewKeyValueList(
+		[]variant.KeyValue{
+			{Key: "empty", Value: variant.NewEmpty()},
+			{Key: "int", Value: variant.NewInt(-123)},
+			{Key: "float", Value: variant.NewFloat64(1.5)},
+			{Key: "short", Value: variant.NewString("abc")},
+			{Key: "long", Value: variant.NewString("a longer string")},
+			{Key: "bytes", Value: variant.NewBytes([]byte{1, 2, 3})},
+			{Key: "ints", Value: variant.NewInt64Array([]int64{1, -2})},
+			{Key: "floats", Value: variant.NewFloat64Array([]float64{0.5})},
+			{Key: "strings", Value: variant.NewStringArray([]string{"a", "b"})},
+			{
+				Key: "list", Value: variant.NewValueList(
+					[]variant.Variant{
+						variant.NewInt(1),
+						variant.NewValueList(nil),
+						variant.NewKeyValueList([]variant.KeyValue{{Key: "k", Value: variant.NewString("v")}}),
+					},
+				),
+			},
+		},
+	)
+}
+
+func TestFromVariant(t *testing.T) {
+	src := createConvertTestValue()
+	v := FromVariant(src)
+	assert.EqualValues(t, TypeKeyValueList, v.Type())
+	assert.EqualValues(t, src.String(), v.String())
+
+	// Conversion back produces an equal value.
+	assert.EqualValues(t, src.String(), ToVariant(v).String())
+}
+
+func TestToVariant(t *testing.T) {
+	v := NewValueList(
+		[]Variant{
+			NewEmpty(),
+			NewInt(minInt),
+			NewFloat64(-2.5),
+			NewString("hello, world"),
+			NewBytes([]byte{0xFF}),
+			NewKeyValueList([]KeyValue{{Key: "a", Value: NewValueList([]Variant{NewInt(1)})}}),
+		},
+	)
+	r := ToVariant(v)
+	assert.EqualValues(t, variant.TypeValueList, r.Type())
+	assert.EqualValues(t, v.String(), r.String())
+	assert.EqualValues(t, v.String(), FromVariant(r).String())
+}
+
+func TestConvertSharesStorage(t *testing.T) {
+	b := []byte{1, 2, 3}
+	v := FromVariant(variant.NewBytes(b))
+	b[0] = 10
+	assert.EqualValues(t, []byte{10, 2, 3}, v.Bytes())
+
+	r := ToVariant(v)
+	b[1] = 20
+	assert.EqualValues(t, []byte{10, 20, 3}, r.Bytes())
+
+	arr := []int64{1, 2}
+	a := FromVariant(variant.NewInt64Array(arr))
+	arr[0] = 10
+	assert.EqualValues(t, []int64{10, 2}, a.Int64Array())
+	ra := ToVariant(a)
+	arr[1] = 20
+	assert.EqualValues(t, []int64{10, 20}, ra.Int64Array())
+}
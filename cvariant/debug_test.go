@@ -0,0 +1,18 @@
+// +build variantdebug
+
+package cvariant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugCheck(t *testing.T) {
+	v := Variant{bits: uint64(TypeInt)}
+	assert.PanicsWithError(t, "invalid Variant: Int does not point to the type marker", func() { v.IntVal() })
+	assert.Panics(t, func() { v.Int() })
+
+	v = NewInt(1)
+	assert.NotPanics(t, func() { v.IntVal() })
+}
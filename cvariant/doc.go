@@ -0,0 +1,25 @@
+/*
+Package cvariant implements a compact Variant data type.
+
+The API of this package is the same as the API of the "variant" package. Compared to
+the Variant type found in the "variant" package the "cvariant" implementation trades
+a small amount of speed for 8 byte reduction of space per Variant on 64 bit systems
+and 4 bytes on 32 bit systems. This is useful for applications that keep a large number
+of values in memory.
+
+On 64 bit systems the size of Variant is 16 bytes (24 bytes for variant.Variant), on 32
+bit systems it is 12 bytes (16 bytes for variant.Variant). The implementation does not
+depend on GOARCH.
+
+The space is saved by packing the length and the capacity of slice-based types
+in the same field as the type. As a result the capacity of a byte slice or a list
+stored in Variant is limited to MaxSliceCap, which is about 500 million. Slices with
+a larger capacity are stored with the capacity trimmed to the length (see NewBytes
+and NewBytesClipped). The maximum length of strings and slices is MaxSliceLen.
+
+Use FromVariant and ToVariant to convert values between this package and the
+"variant" package. JSON, binary, database/sql and log/slog integrations of the
+"variant" package are available for Variant too and use the same encodings, which
+allows to encode a value using one package and decode it using the other.
+*/
+package cvariant
@@ -0,0 +1,49 @@
+package cvariant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPanicErrors(t *testing.T) {
+	recoverErr := func(f func()) (err error) {
+		defer Recover(&err)
+		f()
+		return nil
+	}
+
+	v := NewFloat64(1)
+	err := recoverErr(func() { v.StringVal() })
+	assert.EqualValues(t, &TypeMismatchError{Want: TypeString, Got: TypeFloat64}, err)
+	err = recoverErr(func() { v.Bytes() })
+	assert.EqualValues(t, &TypeMismatchError{Want: TypeBytes, Got: TypeFloat64}, err)
+	err = recoverErr(func() { v.Resize(0) })
+	assert.EqualValues(t, &TypeMismatchError{Want: AnySliceType, Got: TypeFloat64}, err)
+
+	v = NewValueList(make([]Variant, 2, 3))
+	err = recoverErr(func() { v.ValueAt(2) })
+	assert.EqualValues(t, &IndexError{Index: 2, Len: 2}, err)
+	err = recoverErr(func() { v.Resize(4) })
+	assert.EqualValues(t, &LengthError{Len: 4, Max: 3}, err)
+
+	// The capacity of clipped slices is equal to the length.
+	v = NewBytesClipped(make([]byte, 2, 3))
+	err = recoverErr(func() { v.Resize(3) })
+	assert.EqualValues(t, &LengthError{Len: 3, Max: 2}, err)
+
+	v = NewString("abc")
+	err = recoverErr(func() { v.Resize(1) })
+	assert.EqualValues(t, &LengthError{Len: 1, Max: 0}, err)
+
+	assert.Panics(t, func() { recoverErr(func() { panic("other") }) })
+}
+
+func TestInvalidTypeError(t *testing.T) {
+	if debug {
+		t.Skip("the debug checks panic before the type is checked")
+	}
+	v := Variant{bits: 10}
+	assert.PanicsWithError(t, "invalid Variant type 10", func() { _ = v.String() })
+	assert.PanicsWithError(t, "invalid Variant type 10", func() { ToVariant(v) })
+}
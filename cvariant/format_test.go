@@ -0,0 +1,41 @@
+package cvariant
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	v := NewKeyValueList([]KeyValue{
+		{Key: "b", Value: NewValueList([]Variant{NewInt(1), NewFloat64(2.5)})},
+		{Key: "a", Value: NewString("hello")},
+		{Key: "c", Value: NewBytes([]byte{0xAF, 0xCD, 0x34})},
+	})
+
+	assert.EqualValues(t, `{"b":[1,2.5],"a":"hello","c":0xAFCD34}`, Format(v, Printer{}))
+	assert.EqualValues(
+		t,
+		`{"a":"he"…(3 more),"b":[1,2.5],…(1 more)}`,
+		Format(v, Printer{MaxElems: 2, MaxStringLen: 2, SortKeys: true}),
+	)
+	assert.EqualValues(
+		t,
+		"base64(r80=)…(1 more)",
+		Format(NewBytes([]byte{0xAF, 0xCD, 0x34}), Printer{MaxStringLen: 2, Bytes: BytesBase64}),
+	)
+	assert.EqualValues(t, `{"b":[…(2 more)],"a":"hello","c":0xAFCD34}`, Format(v, Printer{MaxDepth: 1}))
+
+	assert.EqualValues(t, v.String(), fmt.Sprintf("%v", v))
+	assert.EqualValues(t, "[\n  1\n]", fmt.Sprintf("%+v", NewValueList([]Variant{NewInt(1)})))
+}
+
+func TestGoString(t *testing.T) {
+	v := NewKeyValueList([]KeyValue{{Key: "k", Value: NewBytes([]byte{1})}})
+	expected := `cvariant.NewKeyValueList([]cvariant.KeyValue{{Key: "k", Value: cvariant.NewBytes([]byte{0x1})}})`
+	assert.EqualValues(t, expected, v.GoString())
+	assert.EqualValues(t, expected, fmt.Sprintf("%#v", v))
+	assert.EqualValues(t, "cvariant.NewFloat64(math.NaN())", NewFloat64(math.NaN()).GoString())
+}
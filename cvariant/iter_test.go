@@ -0,0 +1,39 @@
+//go:build go1.23
+// +build go1.23
+
+package cvariant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIterators(t *testing.T) {
+	v := MustParse(`{"a":[1,2],"b":"x"}`)
+
+	var keys []string
+	for k := range v.Keys() {
+		keys = append(keys, k)
+	}
+	assert.EqualValues(t, []string{"a", "b"}, keys)
+
+	var pairs []string
+	for k, val := range v.Pairs() {
+		pairs = append(pairs, k+"="+val.String())
+	}
+	assert.EqualValues(t, []string{"a=[1,2]", `b="x"`}, pairs)
+
+	sum := 0
+	list := v.KeyValueAt(0).Value
+	for i, e := range list.Values() {
+		sum += i * e.IntVal()
+	}
+	assert.EqualValues(t, 2, sum)
+
+	var paths []string
+	for path := range v.All() {
+		paths = append(paths, path.String())
+	}
+	assert.EqualValues(t, []string{"", `["a"]`, `["a"][0]`, `["a"][1]`, `["b"]`}, paths)
+}
@@ -0,0 +1,28 @@
+package cvariant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	s := `{"a":[1,2.5,0xFF,"long string value"],"b":,"c":{}}`
+	v, err := Parse(s)
+	require.NoError(t, err)
+	assert.EqualValues(t, s, v.String())
+	assert.EqualValues(
+		t,
+		`cvariant.NewKeyValueList([]cvariant.KeyValue{`+
+			`{Key: "a", Value: cvariant.NewValueList([]cvariant.Variant{cvariant.NewInt(1), cvariant.NewFloat64(2.5), `+
+			`cvariant.NewBytes([]byte{0xff}), cvariant.NewString("long string value")})}, `+
+			`{Key: "b", Value: cvariant.NewEmpty()}, `+
+			`{Key: "c", Value: cvariant.NewKeyValueList(nil)}})`,
+		v.GoString(),
+	)
+
+	_, err = Parse("[1 2]")
+	assert.EqualError(t, err, "invalid Variant literal at offset 3: expected ',' or ']', found '2'")
+	assert.Panics(t, func() { MustParse("{") })
+}
@@ -0,0 +1,11 @@
+//go:build go1.21
+// +build go1.21
+
+package cvariant
+
+import "log/slog"
+
+// LogValue implements slog.LogValuer interface. See variant.Variant.LogValue.
+func (v Variant) LogValue() slog.Value {
+	return ToVariant(v).LogValue()
+}
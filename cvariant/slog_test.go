@@ -0,0 +1,27 @@
+//go:build go1.21
+// +build go1.21
+
+package cvariant
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{ReplaceAttr: removeTime}))
+	v := NewKeyValueList([]KeyValue{{Key: "a", Value: NewInt(1)}, {Key: "b", Value: NewString("x")}})
+	logger.Info("msg", "v", v)
+	assert.EqualValues(t, "level=INFO msg=msg v.a=1 v.b=x\n", buf.String())
+}
+
+func removeTime(groups []string, a slog.Attr) slog.Attr {
+	if a.Key == slog.TimeKey && len(groups) == 0 {
+		return slog.Attr{}
+	}
+	return a
+}
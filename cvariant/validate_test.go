@@ -0,0 +1,61 @@
+package cvariant
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	vals := []Variant{
+		NewEmpty(),
+		NewInt(-1),
+		NewFloat64(1.5),
+		NewString(""),
+		NewString("abc"),
+		NewBytesClipped(make([]byte, 1, 10)),
+		NewValueList([]Variant{NewInt(7), NewKeyValueList([]KeyValue{{Key: "k", Value: NewBytes(nil)}})}),
+	}
+	for _, v := range vals {
+		assert.NoError(t, v.Validate(), v.String())
+	}
+
+	b := []byte("abc")
+	tests := []struct {
+		v   Variant
+		err string
+	}{
+		{
+			Variant{bits: 10},
+			"invalid Variant: invalid type 10",
+		},
+		{
+			// An int value of 1 that lost its type marker.
+			Variant{bits: uint64(TypeInt)},
+			"invalid Variant: Int does not point to the type marker",
+		},
+		{
+			Variant{ptr: unsafe.Pointer(&b[0]), bits: 3<<lenFieldShiftCount | uint64(TypeString)},
+			"invalid Variant: String is not clipped",
+		},
+		{
+			Variant{bits: 3<<lenFieldShiftCount | clippedFlag | uint64(TypeString)},
+			"invalid Variant: String of len 3 has nil pointer",
+		},
+		{
+			Variant{ptr: unsafe.Pointer(&b[0]), bits: 4<<lenFieldShiftCount | 3<<capFieldShiftCount | uint64(TypeBytes)},
+			"invalid Variant: Bytes len 4 exceeds capacity 3",
+		},
+		{
+			NewValueList([]Variant{{bits: uint64(TypeFloat64)}}),
+			"invalid Variant at [0]: Float64 does not point to the type marker",
+		},
+	}
+	for _, test := range tests {
+		err := test.v.Validate()
+		if assert.Error(t, err) {
+			assert.EqualValues(t, test.err, err.Error())
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package cvariant
+
+//go:generate go run ../internal/gen
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"unsafe"
+)
+
+// Number of bits to use for Type field. This should be wide enough to fit all Type values.
+const typeFieldBitCount = 4
+
+// Bit mask for Type part of bits field.
+const typeFieldMask = (1 << typeFieldBitCount) - 1
+
+// Slice-based types store Len and Cap in the bits above the Type field in one of
+// two forms. If the clippedFlag bit is not set Len uses lenFieldBitCount bits and Cap
+// uses the rest. If clippedFlag is set the capacity equals the length (or is 0 for
+// TypeString) and Len uses all bits above the flag. The second form is used for
+// strings and for slices which capacity does not fit in capFieldBitCount bits.
+// Len starts at the same bit in both forms, so reading it does not need a branch.
+const clippedFlag = 1 << typeFieldBitCount
+
+const lenFieldBitCount = 30
+const lenFieldShiftCount = typeFieldBitCount + 1
+const lenFieldMask = (1 << lenFieldBitCount) - 1
+
+const capFieldShiftCount = lenFieldShiftCount + lenFieldBitCount
+const capFieldBitCount = 64 - capFieldShiftCount
+const capFieldMask = (1 << capFieldBitCount) - 1
+
+// MaxSliceCap is the maximum capacity of a slice that can be stored in Variant.
+// Byte slices and lists with a larger capacity are stored with the capacity trimmed
+// to the length of the slice.
+const MaxSliceCap = capFieldMask
+
+// Maximum value of Len for clipped slices, which is 1<<59 - 1: Len uses the bits above
+// the Type field and clippedFlag.
+const clippedLenMax = (1 << (64 - lenFieldShiftCount)) - 1
+
+// MaxSliceLen is the maximum length of a slice-type that can be stored in Variant,
+// which is 1<<59 - 1 on 64 bit systems and maxint on 32 bit systems. It is the smaller
+// of maxint and clippedLenMax, which are both masks of all ones, so the smaller one
+// can be calculated as bitwise AND of them.
+const MaxSliceLen = int(uint64(^uint(0)>>1) & clippedLenMax)
+
+// A slice of Type values which is used as a marker of the type to which the Variant's
+// ptr field points to for non pointer types.
+var intTypeMarker = TypeInt
+var floatTypeMarker = TypeFloat64
+
+// Variant allows to store values of one of the Type data types.
+//
+// To create a Variant use one of New* functions in this package. Note that
+// zero-initialized value of Variant is a valid TypeEmpty value.
+// To access the stored value call one of the *Val methods of Variant struct.
+type Variant struct {
+	// Pointer to the slice start for slice-based types, or a pointer to one of
+	// the type markers for TypeInt and TypeFloat64.
+	ptr unsafe.Pointer
+
+	// Type, Cap and Len fields for slice-based types, or the value for TypeInt and
+	// TypeFloat64. Type uses typeFieldBitCount least significant bits, see clippedFlag
+	// for the format of Cap and Len.
+	bits uint64
+}
+
+// The functions below are the encoding primitives that are used by the generated
+// API in api_gen.go. See internal/gen for details.
+
+func (v *Variant) typ() Type {
+	switch v.ptr {
+	case unsafe.Pointer(&intTypeMarker):
+		return TypeInt
+	case unsafe.Pointer(&floatTypeMarker):
+		return TypeFloat64
+	}
+	return Type(v.bits & typeFieldMask)
+}
+
+func newInt(v int) Variant {
+	return Variant{
+		ptr:  unsafe.Pointer(&intTypeMarker),
+		bits: uint64(v),
+	}
+}
+
+func (v *Variant) intVal() int {
+	return int(v.bits)
+}
+
+func newFloat64(v float64) Variant {
+	return Variant{
+		ptr:  unsafe.Pointer(&floatTypeMarker),
+		bits: math.Float64bits(v),
+	}
+}
+
+func (v *Variant) float64Val() float64 {
+	return math.Float64frombits(v.bits)
+}
+
+func newString(v string) Variant {
+	return Variant{
+		ptr:  stringData(v),
+		bits: uint64(len(v))<<lenFieldShiftCount | (clippedFlag | uint64(TypeString)),
+	}
+}
+
+func newStringFromBytes(v []byte) Variant {
+	return Variant{
+		ptr:  bytesData(v),
+		bits: uint64(len(v))<<lenFieldShiftCount | (clippedFlag | uint64(TypeString)),
+	}
+}
+
+// stringVal does not check clippedFlag since strings are always clipped.
+func (v *Variant) stringVal() string {
+	return makeString(v.ptr, int(v.bits>>lenFieldShiftCount))
+}
+
+// newSlice trims the capacity to len if it exceeds MaxSliceCap.
+func newSlice(p unsafe.Pointer, len, cap int, t Type) Variant {
+	bits := uint64(len)<<lenFieldShiftCount | uint64(cap)<<capFieldShiftCount
+	if cap > MaxSliceCap {
+		bits = uint64(len)<<lenFieldShiftCount | clippedFlag
+	}
+	return Variant{ptr: p, bits: bits | uint64(t)}
+}
+
+// lenMask returns the mask of Len after shifting it by lenFieldShiftCount. The mask
+// includes all bits if clippedFlag is set.
+func (v *Variant) lenMask() uint64 {
+	return lenFieldMask | -(v.bits >> typeFieldBitCount & 1)
+}
+
+// sliceLen duplicates the expression of lenMask, since the call adds to the inlining
+// cost of the accessors that use sliceLen.
+func (v *Variant) sliceLen() int {
+	return int(v.bits >> lenFieldShiftCount & (lenFieldMask | -(v.bits >> typeFieldBitCount & 1)))
+}
+
+// sliceLenCap selects the capacity with a mask instead of a branch: clipped is all
+// ones if clippedFlag is set and zero otherwise.
+func (v *Variant) sliceLenCap() (len, cap int) {
+	clipped := -(v.bits >> typeFieldBitCount & 1)
+	l := v.bits >> lenFieldShiftCount & (lenFieldMask | clipped)
+	return int(l), int(l&clipped | v.bits>>capFieldShiftCount&^clipped)
+}
+
+// setSliceLen reduces the capacity of clipped slices to len, since it is equal to
+// the length.
+func (v *Variant) setSliceLen(len int) {
+	v.bits = v.bits&^(v.lenMask()<<lenFieldShiftCount) | uint64(len)<<lenFieldShiftCount
+}
+
+// checkLayout checks the fields of v that are specific to this layout. The slice-based
+// types other than TypeString are checked by the generated code.
+func (v *Variant) checkLayout(t Type) error {
+	switch t {
+	case TypeEmpty:
+		if v.ptr != nil || v.bits != 0 {
+			return errors.New("Empty has non-zero fields")
+		}
+	case TypeInt, TypeFloat64:
+		// typ returns these types without a marker if the Type field of bits is
+		// corrupted.
+		if v.ptr != unsafe.Pointer(&intTypeMarker) && v.ptr != unsafe.Pointer(&floatTypeMarker) {
+			return fmt.Errorf("%v does not point to the type marker", t)
+		}
+		if intTypeMarker != TypeInt || floatTypeMarker != TypeFloat64 {
+			return errors.New("type markers are modified")
+		}
+	case TypeString:
+		if v.bits&clippedFlag == 0 {
+			return errors.New("String is not clipped")
+		}
+		if n := v.bits >> lenFieldShiftCount; n > 0 && v.ptr == nil {
+			return fmt.Errorf("String of len %d has nil pointer", n)
+		}
+	}
+	return nil
+}
@@ -1,10 +1,8 @@
-// +build amd64
-
 package cvariant
 
 import (
 	"fmt"
-	"reflect"
+	"math"
 	"runtime"
 	"strconv"
 	"testing"
@@ -15,7 +13,7 @@ import (
 	"github.com/tigrannajaryan/govariant/internal/testutil"
 )
 
-func ExampleVariant_NewInt() {
+func ExampleNewInt() {
 	v := NewInt(123)
 	if v.Type() == TypeInt {
 		fmt.Print(v.IntVal())
@@ -24,21 +22,83 @@ func ExampleVariant_NewInt() {
 	// Output: 123
 }
 
+func TestType(t *testing.T) {
+	names := map[Type]string{
+		TypeEmpty:        "Empty",
+		TypeInt:          "Int",
+		TypeFloat64:      "Float64",
+		TypeString:       "String",
+		TypeBytes:        "Bytes",
+		TypeValueList:    "ValueList",
+		TypeKeyValueList: "KeyValueList",
+		TypeInt64Array:   "Int64Array",
+		TypeFloat64Array: "Float64Array",
+		TypeStringArray:  "StringArray",
+	}
+	for typ, name := range names {
+		assert.EqualValues(t, name, typ.String())
+
+		parsed, err := ParseType(name)
+		assert.NoError(t, err)
+		assert.EqualValues(t, typ, parsed)
+
+		text, err := typ.MarshalText()
+		assert.NoError(t, err)
+		assert.EqualValues(t, name, string(text))
+
+		var unmarshaled Type
+		assert.NoError(t, unmarshaled.UnmarshalText(text))
+		assert.EqualValues(t, typ, unmarshaled)
+	}
+
+	assert.EqualValues(t, "Type(10)", Type(10).String())
+	assert.EqualValues(t, "Type(-1)", Type(-1).String())
+	assert.EqualValues(t, "Int", fmt.Sprintf("%v", TypeInt))
+
+	_, err := Type(10).MarshalText()
+	assert.EqualError(t, err, "cannot marshal invalid Variant type 10")
+
+	for _, name := range []string{"", "int", "TypeInt", "Type(10)"} {
+		_, err := ParseType(name)
+		assert.EqualError(t, err, fmt.Sprintf("unknown Variant type name %q", name))
+	}
+	var typ Type
+	assert.Error(t, typ.UnmarshalText([]byte("Foo")))
+}
+
 func TestVariantFieldAliasing(t *testing.T) {
 	v := Variant{}
 
-	// Ensure fields correctly alias corresponding fields of StringHeader
+	// Ensure ptr field can hold the pointer to the data of a string or a slice.
+	assert.EqualValues(t, unsafe.Sizeof(uintptr(0)), unsafe.Sizeof(v.ptr))
 
-	// Data/ptr field.
-	assert.EqualValues(t, unsafe.Sizeof(reflect.StringHeader{}.Data), unsafe.Sizeof(v.ptr))
+	// Ensure float64 can correctly fit in bits
+	assert.EqualValues(t, unsafe.Sizeof(float64(0.0)), unsafe.Sizeof(v.bits))
 
-	// Ensure fields correctly alias corresponding fields of SliceHeader
+	// Variant is 8 bytes larger than a pointer on all architectures.
+	assert.EqualValues(t, unsafe.Sizeof(uintptr(0))+8, unsafe.Sizeof(v))
+}
 
-	// Data/ptr field.
-	assert.EqualValues(t, unsafe.Sizeof(reflect.SliceHeader{}.Data), unsafe.Sizeof(v.ptr))
+func TestVariantSliceLimits(t *testing.T) {
+	assert.EqualValues(t, 1<<29-1, MaxSliceCap)
+	if strconv.IntSize == 64 {
+		assert.EqualValues(t, uint64(1<<59-1), uint64(MaxSliceLen))
+	} else {
+		assert.EqualValues(t, math.MaxInt32, MaxSliceLen)
+	}
 
-	// Ensure float64 can correctly fit in capOrVal
-	assert.EqualValues(t, unsafe.Sizeof(float64(0.0)), unsafe.Sizeof(v.bits))
+	// Len and Cap are decoded from both forms.
+	b := make([]byte, 3, 5)
+	v := newSlice(bytesData(b), 3, 5, TypeBytes)
+	l, c := v.sliceLenCap()
+	assert.EqualValues(t, []int{3, 5}, []int{l, c})
+	v = newSlice(bytesData(b), 3, MaxSliceCap+1, TypeBytes)
+	l, c = v.sliceLenCap()
+	assert.EqualValues(t, []int{3, 3}, []int{l, c})
+	v = newSlice(bytesData(b), MaxSliceLen, MaxSliceLen, TypeBytes)
+	l, c = v.sliceLenCap()
+	assert.EqualValues(t, []int{MaxSliceLen, MaxSliceLen}, []int{l, c})
+	assert.EqualValues(t, MaxSliceLen, v.Len())
 }
 
 func TestVariant(t *testing.T) {
@@ -161,7 +221,6 @@ func TestVariantGC(t *testing.T) {
 	var v1 Variant
 	s1 := strconv.Itoa(1234)
 	v1 = NewString(s1)
-	v1 = v1
 
 	for i := 0; i < 10000; i++ {
 		s1 := strconv.Itoa(i)
@@ -187,7 +246,8 @@ func TestVariantGC(t *testing.T) {
 	runtime.ReadMemStats(&ms)
 
 	s2 = v.StringVal()
-	s2 = s2
+	assert.EqualValues(t, "1234", s2)
+	assert.EqualValues(t, "1234", v1.StringVal())
 }
 
 func TestVariantPanics(t *testing.T) {
@@ -210,6 +270,72 @@ func TestVariantPanics(t *testing.T) {
 	}
 }
 
+func TestCheckedAccessors(t *testing.T) {
+	b := []byte{1, 2}
+	list := []Variant{NewInt(1)}
+	kvl := []KeyValue{{Key: "k", Value: NewInt(1)}}
+	vals := []Variant{
+		NewEmpty(),
+		NewInt(123),
+		NewFloat64(1.5),
+		NewString("abc"),
+		NewBytes(b),
+		NewValueList(list),
+		NewKeyValueList(kvl),
+	}
+	for _, v := range vals {
+		t.Run(v.String(), func(t *testing.T) {
+			i, ok := v.Int()
+			assert.EqualValues(t, v.Type() == TypeInt, ok)
+			if ok {
+				assert.EqualValues(t, 123, i)
+			} else {
+				assert.EqualValues(t, 0, i)
+			}
+
+			f, ok := v.Float64()
+			assert.EqualValues(t, v.Type() == TypeFloat64, ok)
+			if ok {
+				assert.EqualValues(t, 1.5, f)
+			} else {
+				assert.EqualValues(t, 0, f)
+			}
+
+			s, ok := v.Str()
+			assert.EqualValues(t, v.Type() == TypeString, ok)
+			if ok {
+				assert.EqualValues(t, "abc", s)
+			} else {
+				assert.EqualValues(t, "", s)
+			}
+
+			bv, ok := v.BytesOk()
+			assert.EqualValues(t, v.Type() == TypeBytes, ok)
+			if ok {
+				assert.EqualValues(t, b, bv)
+			} else {
+				assert.Nil(t, bv)
+			}
+
+			lv, ok := v.ListOk()
+			assert.EqualValues(t, v.Type() == TypeValueList, ok)
+			if ok {
+				assert.EqualValues(t, list, lv)
+			} else {
+				assert.Nil(t, lv)
+			}
+
+			kv, ok := v.MapOk()
+			assert.EqualValues(t, v.Type() == TypeKeyValueList, ok)
+			if ok {
+				assert.EqualValues(t, kvl, kv)
+			} else {
+				assert.Nil(t, kv)
+			}
+		})
+	}
+}
+
 func TestResize(t *testing.T) {
 	v := NewBytes([]byte("abc"))
 	assert.EqualValues(t, 3, v.Len())
@@ -227,6 +353,18 @@ func TestResize(t *testing.T) {
 	assert.Panics(t, func() { v.Resize(4) })
 }
 
+func TestNewBytesClipped(t *testing.T) {
+	b := make([]byte, 3, 10)
+	v := NewBytesClipped(b)
+	assert.EqualValues(t, 3, v.Len())
+	assert.EqualValues(t, 3, cap(v.Bytes()))
+	assert.Panics(t, func() { v.Resize(4) })
+
+	// Appending reallocates instead of overwriting the bytes after the end of b.
+	_ = append(v.Bytes(), 1)
+	assert.EqualValues(t, 0, b[:4][3])
+}
+
 func createVariantInt() Variant {
 	for i := 0; i < 1; i++ {
 		return NewInt(testutil.IntMagicVal)
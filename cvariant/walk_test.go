@@ -0,0 +1,30 @@
+package cvariant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalk(t *testing.T) {
+	v := MustParse(`{"a":[1,2],"b":"x"}`)
+	var paths []string
+	err := Walk(
+		&v,
+		func(path Path, v *Variant) error {
+			if v.Type() == TypeInt {
+				*v = NewInt(v.IntVal() + 1)
+			}
+			return nil
+		},
+		func(path Path, v *Variant) error {
+			paths = append(paths, path.String())
+			return nil
+		},
+	)
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{`["a"][0]`, `["a"][1]`, `["a"]`, `["b"]`, ""}, paths)
+	assert.EqualValues(t, `{"a":[2,3],"b":"x"}`, v.String())
+
+	assert.NoError(t, Walk(&v, func(Path, *Variant) error { return SkipAll }, nil))
+}
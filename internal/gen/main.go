@@ -0,0 +1,176 @@
+// Command gen generates the public API of Variant for the packages that implement
+// Variant using different memory layouts (currently "variant" and "cvariant").
+//
+// The API is defined once in api.go.tmpl. Each layout supplies only its encoding
+// primitives, which are unexported methods and functions that the generated code
+// calls:
+//
+//	func (v *Variant) typ() Type
+//	func newInt(v int) Variant
+//	func (v *Variant) intVal() int
+//	func newFloat64(v float64) Variant
+//	func (v *Variant) float64Val() float64
+//	func newString(v string) Variant
+//	func newStringFromBytes(v []byte) Variant
+//	func (v *Variant) stringVal() string
+//	func newSlice(p unsafe.Pointer, len, cap int, t Type) Variant
+//	func (v *Variant) sliceLen() int
+//	func (v *Variant) sliceLenCap() (len, cap int)
+//	func (v *Variant) setSliceLen(len int)
+//	func (v *Variant) checkLayout(t Type) error
+//
+// The Variant struct must have a "ptr unsafe.Pointer" field that points to the
+// first element of slice-based types. The primitives may assume that the arguments
+// are already validated, e.g. the string and slice lengths do not exceed the maximum
+// length. sliceLenCap is not called for TypeString, which has zero capacity.
+// checkLayout returns an error if the fields of v are not a valid encoding of type t,
+// which is in the range of Type values. It must not call the public API, since the
+// API calls it in builds with the variantdebug tag.
+//
+// The generator is run by "go generate" in the directory of the package and uses
+// $GOPACKAGE to select the layout. To add a new layout add an entry to layouts and
+// a go:generate directive to the package.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// layout describes a package that implements Variant.
+type layout struct {
+	// Name of the package.
+	Package string
+
+	// Name of the constant that holds the maximum length of slice-based types.
+	MaxSliceLen string
+
+	// Additional paragraphs of doc comments for the generated functions, keyed
+	// by function name. Used to document layout-specific behavior.
+	Docs map[string]string
+}
+
+var layouts = map[string]layout{
+	"variant": {
+		Package:     "variant",
+		MaxSliceLen: "maxSliceLen",
+	},
+	"cvariant": {
+		Package:     "cvariant",
+		MaxSliceLen: "MaxSliceLen",
+		Docs: map[string]string{
+			"NewBytes": `If cap(v) exceeds MaxSliceCap the capacity is trimmed to len(v), i.e. the Variant
+stores v[:len(v):len(v)].`,
+			"NewBytesClipped": `The capacity of byte slices created by NewBytesClipped is not limited by MaxSliceCap.`,
+			"NewValueList":    `If cap(v) exceeds MaxSliceCap the capacity is trimmed to len(v).`,
+			"NewKeyValueList": `If cap(v) exceeds MaxSliceCap the capacity is trimmed to len(v).`,
+			"NewInt64Array":   `If cap(v) exceeds MaxSliceCap the capacity is trimmed to len(v).`,
+			"NewFloat64Array": `If cap(v) exceeds MaxSliceCap the capacity is trimmed to len(v).`,
+			"NewStringArray":  `If cap(v) exceeds MaxSliceCap the capacity is trimmed to len(v).`,
+			"Resize": `If the capacity of the slice exceeds MaxSliceCap (i.e. it is equal to the
+length, see NewBytes) the capacity is reduced to len.`,
+		},
+	},
+}
+
+// templates maps the template file names to the names of the generated files.
+var templates = map[string]string{
+	"api.go.tmpl":           "api_gen.go",
+	"arena.go.tmpl":         "arena_gen.go",
+	"builder.go.tmpl":       "builder_gen.go",
+	"array.go.tmpl":         "array_gen.go",
+	"coerce.go.tmpl":        "coerce_gen.go",
+	"debug.go.tmpl":         "debug_gen.go",
+	"errors.go.tmpl":        "errors_gen.go",
+	"format.go.tmpl":        "format_gen.go",
+	"iter.go.tmpl":          "iter_gen.go",
+	"nodebug.go.tmpl":       "nodebug_gen.go",
+	"parse.go.tmpl":         "parse_gen.go",
+	"unsafe.go.tmpl":        "unsafe_gen.go",
+	"unsafe_legacy.go.tmpl": "unsafe_legacy_gen.go",
+	"validate.go.tmpl":      "validate_gen.go",
+	"walk.go.tmpl":          "walk_gen.go",
+}
+
+func main() {
+	pkg := flag.String("pkg", os.Getenv("GOPACKAGE"), "package to generate the code for")
+	dir := flag.String("dir", ".", "output directory")
+	flag.Parse()
+
+	l, ok := layouts[*pkg]
+	if !ok {
+		log.Fatalf("unknown package %q", *pkg)
+	}
+
+	files, err := generate(templateDir(), l)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for name, src := range files {
+		if err := ioutil.WriteFile(filepath.Join(*dir, name), src, 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// templateDir returns the directory that contains the templates, which is the
+// directory of this source file.
+func templateDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}
+
+// generate executes all templates for the layout l and returns the formatted
+// source code keyed by the generated file name.
+func generate(dir string, l layout) (map[string][]byte, error) {
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	files := map[string][]byte{}
+	for _, name := range names {
+		tmpl, err := template.New(name).Funcs(template.FuncMap{"doc": l.doc}).ParseFiles(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, l); err != nil {
+			return nil, err
+		}
+		src, err := format.Source(buf.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		files[templates[name]] = src
+	}
+	return files, nil
+}
+
+// doc returns the additional doc comment paragraph for function fn, formatted
+// as comment lines that start with an empty comment line. Returns an empty string
+// if the layout does not have additional docs for fn.
+func (l layout) doc(fn string) string {
+	text, ok := l.Docs[fn]
+	if !ok {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n//")
+	for _, line := range strings.Split(text, "\n") {
+		b.WriteString("\n// " + line)
+	}
+	return b.String()
+}
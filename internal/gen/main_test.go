@@ -0,0 +1,34 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGeneratedFilesUpToDate verifies that the generated files in each package match
+// the output of the generator, i.e. that "go generate ./..." was run after changing
+// the templates.
+func TestGeneratedFilesUpToDate(t *testing.T) {
+	for pkg, l := range layouts {
+		files, err := generate(".", l)
+		require.NoError(t, err)
+		assert.Len(t, files, len(templates))
+
+		for name, src := range files {
+			path := filepath.Join("..", "..", pkg, name)
+			existing, err := ioutil.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, string(src), string(existing), "%s is out of date, run go generate ./...", path)
+		}
+	}
+}
+
+func TestLayoutDoc(t *testing.T) {
+	l := layout{Docs: map[string]string{"F": "line 1\nline 2"}}
+	assert.Equal(t, "\n//\n// line 1\n// line 2", l.doc("F"))
+	assert.Equal(t, "", l.doc("G"))
+}
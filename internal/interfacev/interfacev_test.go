@@ -96,7 +96,7 @@ func BenchmarkVariantStringTypeAndGet(b *testing.B) {
 		v := createVariantString()
 		switch val := v.(type) {
 		case *IVariantString:
-			val.String()
+			_ = val.String()
 		default:
 			panic("invalid type")
 		}
@@ -144,7 +144,7 @@ func BenchmarkVariantStringSliceTypeAndGetAll(b *testing.B) {
 		for _, v := range vv {
 			switch val := v.(type) {
 			case *IVariantString:
-				val.String()
+				_ = val.String()
 			default:
 				panic("invalid type")
 			}
@@ -0,0 +1,101 @@
+package variant
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArenaLists(t *testing.T) {
+	var a Arena
+	v := a.NewValueList(3)
+	assert.EqualValues(t, TypeValueList, v.Type())
+	assert.EqualValues(t, []Variant{{}, {}, {}}, v.ValueList())
+
+	kv := a.NewKeyValueList(2)
+	assert.EqualValues(t, TypeKeyValueList, kv.Type())
+	assert.EqualValues(t, []KeyValue{{}, {}}, kv.KeyValueList())
+
+	// Appending to a list must not overwrite the next list in the chunk.
+	w := a.NewValueList(1)
+	list := append(v.ValueList(), NewInt(1))
+	list[0] = NewInt(2)
+	assert.EqualValues(t, TypeEmpty, w.ValueList()[0].Type())
+	assert.EqualValues(t, TypeEmpty, v.ValueList()[0].Type())
+
+	empty := a.NewValueList(0)
+	assert.EqualValues(t, 0, empty.Len())
+	assert.PanicsWithError(t, (&LengthError{Len: -1, Max: maxSliceLen}).Error(), func() { a.NewValueList(-1) })
+	assert.Panics(t, func() { a.NewKeyValueList(-1) })
+}
+
+func TestArenaChunks(t *testing.T) {
+	var a Arena
+	var lists []Variant
+	for i := 0; i < 3*arenaValueChunkLen; i++ {
+		v := a.NewValueList(3)
+		v.ValueList()[2] = NewInt(i)
+		lists = append(lists, v)
+	}
+	for i := range lists {
+		require.EqualValues(t, NewInt(i), lists[i].ValueAt(2))
+	}
+
+	// Lists longer than a chunk are allocated separately.
+	v := a.NewKeyValueList(arenaKeyValueChunkLen + 1)
+	assert.EqualValues(t, arenaKeyValueChunkLen+1, v.Len())
+	assert.EqualValues(t, 0, len(a.keyValueChunks))
+}
+
+func TestArenaCopyString(t *testing.T) {
+	var a Arena
+	b := []byte("a string in a buffer")
+	s := a.CopyString(string(b))
+	b[0] = 'X'
+	assert.EqualValues(t, "a string in a buffer", s)
+	assert.EqualValues(t, "", a.CopyString(""))
+
+	long := strings.Repeat("x", arenaByteChunkLen+1)
+	assert.EqualValues(t, long, a.CopyString(long))
+	assert.EqualValues(t, "a string in a buffer", s)
+}
+
+func TestArenaReset(t *testing.T) {
+	var a Arena
+	build := func() Variant {
+		v := a.NewKeyValueList(2)
+		list := v.KeyValueList()
+		list[0] = KeyValue{Key: a.CopyString("list"), Value: a.NewValueList(2)}
+		list[1] = KeyValue{Key: a.CopyString("key"), Value: NewString(a.CopyString("a long string value"))}
+		list[0].Value.ValueList()[0] = NewInt(1)
+		return v
+	}
+	v := build()
+	assert.EqualValues(t, `{"list":[1,],"key":"a long string value"}`, v.String())
+
+	a.Reset()
+	// The memory is reused and the new lists are empty.
+	v = a.NewValueList(2)
+	assert.EqualValues(t, []Variant{{}, {}}, v.ValueList())
+	a.Reset()
+	allocs := testing.AllocsPerRun(10, func() {
+		build()
+		a.Reset()
+	})
+	assert.EqualValues(t, 0, allocs)
+}
+
+func BenchmarkArenaBuild(b *testing.B) {
+	var a Arena
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < 100; j++ {
+			v := a.NewKeyValueList(2)
+			list := v.KeyValueList()
+			list[0] = KeyValue{Key: a.CopyString("list"), Value: a.NewValueList(3)}
+			list[1] = KeyValue{Key: a.CopyString("string"), Value: NewString(a.CopyString("a long string value"))}
+		}
+		a.Reset()
+	}
+}
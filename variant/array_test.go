@@ -0,0 +1,249 @@
+package variant
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInt64Array(t *testing.T) {
+	arr := []int64{1, math.MinInt64, math.MaxInt64}
+	v := NewInt64Array(arr)
+	assert.EqualValues(t, TypeInt64Array, v.Type())
+	assert.EqualValues(t, 3, v.Len())
+	assert.EqualValues(t, arr, v.Int64Array())
+	assert.NoError(t, v.Validate())
+
+	// The array is not copied.
+	arr[0] = 10
+	assert.EqualValues(t, 10, v.Int64Array()[0])
+	v.Int64Array()[1] = 20
+	assert.EqualValues(t, 20, arr[1])
+
+	v.Resize(1)
+	assert.EqualValues(t, []int64{10}, v.Int64Array())
+	v.Resize(3)
+	assert.EqualValues(t, []int64{10, 20, math.MaxInt64}, v.Int64Array())
+	assert.Panics(t, func() { v.Resize(4) })
+
+	v = NewInt64Array(nil)
+	assert.Nil(t, v.Int64Array())
+	assert.EqualValues(t, 0, v.Len())
+}
+
+func TestFloat64Array(t *testing.T) {
+	arr := make([]float64, 2, 5)
+	arr[0] = 1.5
+	arr[1] = math.Inf(-1)
+	v := NewFloat64Array(arr)
+	assert.EqualValues(t, TypeFloat64Array, v.Type())
+	assert.EqualValues(t, arr, v.Float64Array())
+	assert.EqualValues(t, 5, cap(v.Float64Array()))
+
+	v.Resize(5)
+	assert.EqualValues(t, []float64{1.5, math.Inf(-1), 0, 0, 0}, v.Float64Array())
+}
+
+func TestStringArray(t *testing.T) {
+	arr := []string{"", "abc", "a long string that is not inline"}
+	v := NewStringArray(arr)
+	assert.EqualValues(t, TypeStringArray, v.Type())
+	assert.EqualValues(t, arr, v.StringArray())
+	assert.NoError(t, v.Validate())
+}
+
+func TestArrayTypeMismatch(t *testing.T) {
+	v := NewInt64Array([]int64{1})
+	assert.PanicsWithError(
+		t, "Variant type is Int64Array, want Float64Array", func() { v.Float64Array() },
+	)
+	assert.Panics(t, func() { v.StringArray() })
+	assert.Panics(t, func() { v.ValueList() })
+
+	i := NewInt(1)
+	assert.PanicsWithError(t, "Variant type is Int, want Int64Array", func() { i.Int64Array() })
+}
+
+func TestToValueList(t *testing.T) {
+	tests := []struct {
+		v        Variant
+		expected Variant
+	}{
+		{
+			NewInt64Array([]int64{1, -2}),
+			NewValueList([]Variant{NewInt(1), NewInt(-2)}),
+		},
+		{
+			NewFloat64Array([]float64{1.5, 2}),
+			NewValueList([]Variant{NewFloat64(1.5), NewFloat64(2)}),
+		},
+		{
+			NewStringArray([]string{"a", "b"}),
+			NewValueList([]Variant{NewString("a"), NewString("b")}),
+		},
+		{
+			NewInt64Array(nil),
+			NewValueList([]Variant{}),
+		},
+		{
+			NewValueList([]Variant{NewEmpty()}),
+			NewValueList([]Variant{NewEmpty()}),
+		},
+	}
+	for _, test := range tests {
+		assert.EqualValues(t, test.expected.GoString(), test.v.ToValueList().GoString())
+	}
+
+	// The elements that do not fit into int on 32 bit systems are converted to Float64.
+	x := int64(math.MaxInt64)
+	expected := NewValueList([]Variant{NewInt(int(x)), NewInt(1)})
+	if strconv.IntSize == 32 {
+		expected = NewValueList([]Variant{NewFloat64(float64(x)), NewInt(1)})
+	}
+	v := NewInt64Array([]int64{x, 1})
+	assert.EqualValues(t, expected.GoString(), v.ToValueList().GoString())
+
+	v = NewString("a")
+	assert.PanicsWithError(t, "Variant type is String, want ValueList", func() { v.ToValueList() })
+}
+
+// toArray allows to call ToArray for non-addressable values.
+func toArray(v Variant, typ Type) (Variant, error) {
+	return v.ToArray(typ)
+}
+
+func TestToArray(t *testing.T) {
+	ints := NewValueList([]Variant{NewInt(1), NewInt(-2)})
+	v, err := ints.ToArray(TypeInt64Array)
+	require.NoError(t, err)
+	assert.EqualValues(t, []int64{1, -2}, v.Int64Array())
+
+	// Ints are converted to float64.
+	v, err = toArray(NewValueList([]Variant{NewInt(1), NewFloat64(2.5)}), TypeFloat64Array)
+	require.NoError(t, err)
+	assert.EqualValues(t, []float64{1, 2.5}, v.Float64Array())
+
+	v, err = toArray(NewValueList([]Variant{NewString("a"), NewString("long string value")}), TypeStringArray)
+	require.NoError(t, err)
+	assert.EqualValues(t, []string{"a", "long string value"}, v.StringArray())
+
+	v, err = toArray(NewValueList(nil), TypeStringArray)
+	require.NoError(t, err)
+	assert.EqualValues(t, TypeStringArray, v.Type())
+	assert.EqualValues(t, 0, v.Len())
+
+	// The conversion is reversible.
+	v, err = ints.ToArray(TypeInt64Array)
+	require.NoError(t, err)
+	assert.EqualValues(t, ints.GoString(), v.ToValueList().GoString())
+
+	// An array of the requested type is returned as is.
+	arr := NewFloat64Array([]float64{1})
+	v, err = arr.ToArray(TypeFloat64Array)
+	require.NoError(t, err)
+	assert.EqualValues(t, arr.GoString(), v.GoString())
+}
+
+func TestToArrayErrors(t *testing.T) {
+	_, err := toArray(NewValueList([]Variant{NewInt(1), NewFloat64(2)}), TypeInt64Array)
+	assert.EqualError(t, err, "cannot convert ValueList to Int64Array: element 1 is Float64")
+
+	_, err = toArray(NewValueList([]Variant{NewString("1")}), TypeFloat64Array)
+	assert.EqualError(t, err, "cannot convert ValueList to Float64Array: element 0 is String")
+
+	_, err = toArray(NewValueList([]Variant{NewEmpty()}), TypeStringArray)
+	assert.EqualError(t, err, "cannot convert ValueList to StringArray: element 0 is Empty")
+
+	_, err = toArray(NewValueList(nil), TypeBytes)
+	assert.EqualError(t, err, "cannot convert ValueList to Bytes: not an array type")
+
+	_, err = toArray(NewStringArray(nil), TypeInt64Array)
+	assert.EqualValues(t, &TypeMismatchError{Want: TypeValueList, Got: TypeStringArray}, err)
+}
+
+func TestArrayString(t *testing.T) {
+	tests := []struct {
+		v        Variant
+		expected string
+	}{
+		{NewInt64Array(nil), "int64[]"},
+		{NewInt64Array([]int64{1, -2}), "int64[1,-2]"},
+		{NewFloat64Array([]float64{1.5, 2, math.NaN(), math.Inf(1)}), "float64[1.5,2,NaN,+Inf]"},
+		{NewStringArray([]string{"a", "b\n"}), `string["a","b\n"]`},
+		{
+			NewKeyValueList([]KeyValue{{Key: "a", Value: NewInt64Array([]int64{1})}}),
+			`{"a":int64[1]}`,
+		},
+	}
+	for _, test := range tests {
+		assert.EqualValues(t, test.expected, test.v.String())
+
+		// The output can be parsed back.
+		v, err := Parse(test.expected)
+		require.NoError(t, err, test.expected)
+		assert.EqualValues(t, test.expected, v.String())
+	}
+
+	p := Printer{MaxElems: 2, MaxStringLen: 2, Indent: " "}
+	assert.EqualValues(
+		t, "string[\n \"ab\"…(1 more),\n \"c\",\n …(1 more)\n]",
+		p.Sprint(NewStringArray([]string{"abc", "c", "d"})),
+	)
+}
+
+func TestArrayGoString(t *testing.T) {
+	tests := []struct {
+		v        Variant
+		expected string
+	}{
+		{NewInt64Array(nil), "variant.NewInt64Array(nil)"},
+		{NewInt64Array([]int64{1, -2}), "variant.NewInt64Array([]int64{1, -2})"},
+		{
+			NewFloat64Array([]float64{0.5, math.NaN(), math.Copysign(0, -1)}),
+			"variant.NewFloat64Array([]float64{0.5, math.NaN(), math.Copysign(0, -1)})",
+		},
+		{NewStringArray([]string{}), "variant.NewStringArray([]string{})"},
+		{NewStringArray([]string{"a\""}), `variant.NewStringArray([]string{"a\""})`},
+	}
+	for _, test := range tests {
+		assert.EqualValues(t, test.expected, test.v.GoString())
+	}
+}
+
+func TestParseArray(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected Variant
+	}{
+		{"int64[]", NewInt64Array(nil)},
+		{"int64[ 1 , -9223372036854775808 ]", NewInt64Array([]int64{1, math.MinInt64})},
+		{"float64[1, 2.5, -Inf]", NewFloat64Array([]float64{1, 2.5, math.Inf(-1)})},
+		{"string[\"a\", `b`]", NewStringArray([]string{"a", "b"})},
+		{"[int64[1],string[]]", NewValueList([]Variant{NewInt64Array([]int64{1}), NewStringArray(nil)})},
+	}
+	for _, test := range tests {
+		v, err := Parse(test.s)
+		require.NoError(t, err, test.s)
+		assert.EqualValues(t, test.expected.GoString(), v.GoString(), test.s)
+	}
+
+	errors := []struct {
+		s   string
+		err string
+	}{
+		{"int64[1,]", `invalid Variant literal at offset 8: unexpected ']'`},
+		{"int64[1.5]", "invalid Variant literal at offset 6: invalid value 1.5"},
+		{"int64[9223372036854775808]", "invalid Variant literal at offset 6: number 9223372036854775808 is out of range"},
+		{"float64[a]", "invalid Variant literal at offset 8: invalid value a"},
+		{"string[1]", "invalid Variant literal at offset 7: expected a string, found '1'"},
+		{"int64[1 2]", "invalid Variant literal at offset 8: expected ',' or ']', found '2'"},
+		{"int64[1", "invalid Variant literal at offset 7: expected ',' or ']', found end of input"},
+	}
+	for _, test := range errors {
+		_, err := Parse(test.s)
+		assert.EqualError(t, err, test.err, test.s)
+	}
+}
@@ -0,0 +1,287 @@
+package variant
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// binaryMagic is the prefix of the data produced by MarshalBinary. The first byte
+// is not valid in UTF-8 text, which allows to distinguish binary encoded Variants
+// from text.
+const binaryMagic = "\xffGV\x01"
+
+// maxBinaryDepth is the maximum nesting depth of lists that UnmarshalBinary decodes,
+// which limits the recursion of the decoder for malicious input. It is the same as the
+// limit of encoding/json.
+const maxBinaryDepth = 10000
+
+var errBinaryTruncated = errors.New("invalid Variant binary encoding: data truncated")
+
+// MarshalBinary implements encoding.BinaryMarshaler interface.
+//
+// The encoding is compact: each value is encoded as its Type in one byte followed
+// by the value. Ints are encoded as varints, float64 as 8 bytes, strings and byte
+// slices as uvarint length followed by the bytes, lists as uvarint element count
+// followed by the elements. Arrays are encoded as uvarint element count followed by
+// the elements without the Type byte. The encoding preserves all Variant types exactly.
+func (v Variant) MarshalBinary() ([]byte, error) {
+	return appendBinary([]byte(binaryMagic), v), nil
+}
+
+func appendBinary(b []byte, v Variant) []byte {
+	t := v.Type()
+	b = append(b, byte(t))
+	switch t {
+	case TypeEmpty:
+	case TypeInt:
+		b = appendVarint(b, int64(v.IntVal()))
+	case TypeFloat64:
+		var buf [8]byte
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(v.Float64Val()))
+		b = append(b, buf[:]...)
+	case TypeString:
+		s := v.StringVal()
+		b = appendUvarint(b, uint64(len(s)))
+		b = append(b, s...)
+	case TypeBytes:
+		s := v.Bytes()
+		b = appendUvarint(b, uint64(len(s)))
+		b = append(b, s...)
+	case TypeValueList:
+		list := v.ValueList()
+		b = appendUvarint(b, uint64(len(list)))
+		for _, e := range list {
+			b = appendBinary(b, e)
+		}
+	case TypeKeyValueList:
+		list := v.KeyValueList()
+		b = appendUvarint(b, uint64(len(list)))
+		for _, kv := range list {
+			b = appendUvarint(b, uint64(len(kv.Key)))
+			b = append(b, kv.Key...)
+			b = appendBinary(b, kv.Value)
+		}
+	case TypeInt64Array:
+		arr := v.Int64Array()
+		b = appendUvarint(b, uint64(len(arr)))
+		for _, x := range arr {
+			b = appendVarint(b, x)
+		}
+	case TypeFloat64Array:
+		arr := v.Float64Array()
+		b = appendUvarint(b, uint64(len(arr)))
+		for _, f := range arr {
+			var buf [8]byte
+			binary.LittleEndian.PutUint64(buf[:], math.Float64bits(f))
+			b = append(b, buf[:]...)
+		}
+	case TypeStringArray:
+		arr := v.StringArray()
+		b = appendUvarint(b, uint64(len(arr)))
+		for _, s := range arr {
+			b = appendUvarint(b, uint64(len(s)))
+			b = append(b, s...)
+		}
+	default:
+		panic(&InvalidTypeError{Type: t})
+	}
+	return b
+}
+
+func appendVarint(b []byte, x int64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(buf[:], x)
+	return append(b, buf[:n]...)
+}
+
+func appendUvarint(b []byte, x uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], x)
+	return append(b, buf[:n]...)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler interface. data must be
+// produced by MarshalBinary. Returns an error if the lists are nested deeper than
+// 10000 levels. On 32 bit platforms the ints that do not fit into int, e.g. the ones
+// encoded on a 64 bit platform, are decoded as TypeFloat64.
+//
+// Strings and byte slices in the decoded Variant are copies, data is not
+// referenced after the call returns.
+func (v *Variant) UnmarshalBinary(data []byte) error {
+	if !isBinary(data) {
+		return errors.New("invalid Variant binary encoding: missing header")
+	}
+	d := binaryDecoder{data: data[len(binaryMagic):]}
+	r, err := d.decode()
+	if err != nil {
+		return err
+	}
+	if len(d.data) != 0 {
+		return errors.New("invalid Variant binary encoding: unexpected data after value")
+	}
+	*v = r
+	return nil
+}
+
+func isBinary(data []byte) bool {
+	return len(data) >= len(binaryMagic) && string(data[:len(binaryMagic)]) == binaryMagic
+}
+
+type binaryDecoder struct {
+	data []byte
+
+	// depth is the number of lists that contain the value being decoded.
+	depth int
+}
+
+func (d *binaryDecoder) decode() (Variant, error) {
+	if len(d.data) == 0 {
+		return Variant{}, errBinaryTruncated
+	}
+	t := Type(d.data[0])
+	d.data = d.data[1:]
+
+	switch t {
+	case TypeEmpty:
+		return NewEmpty(), nil
+	case TypeInt:
+		x, n := binary.Varint(d.data)
+		if n <= 0 {
+			return Variant{}, errBinaryTruncated
+		}
+		d.data = d.data[n:]
+		return newIntFromInt64(x), nil
+	case TypeFloat64:
+		if len(d.data) < 8 {
+			return Variant{}, errBinaryTruncated
+		}
+		f := math.Float64frombits(binary.LittleEndian.Uint64(d.data))
+		d.data = d.data[8:]
+		return NewFloat64(f), nil
+	case TypeString:
+		s, err := d.bytes()
+		if err != nil {
+			return Variant{}, err
+		}
+		return NewString(string(s)), nil
+	case TypeBytes:
+		s, err := d.bytes()
+		if err != nil {
+			return Variant{}, err
+		}
+		return NewBytes(append([]byte(nil), s...)), nil
+	case TypeValueList:
+		n, err := d.count()
+		if err != nil {
+			return Variant{}, err
+		}
+		if err := d.enter(); err != nil {
+			return Variant{}, err
+		}
+		list := make([]Variant, n)
+		for i := range list {
+			if list[i], err = d.decode(); err != nil {
+				return Variant{}, err
+			}
+		}
+		d.depth--
+		return NewValueList(list), nil
+	case TypeKeyValueList:
+		n, err := d.count()
+		if err != nil {
+			return Variant{}, err
+		}
+		if err := d.enter(); err != nil {
+			return Variant{}, err
+		}
+		list := make([]KeyValue, n)
+		for i := range list {
+			key, err := d.bytes()
+			if err != nil {
+				return Variant{}, err
+			}
+			list[i].Key = string(key)
+			if list[i].Value, err = d.decode(); err != nil {
+				return Variant{}, err
+			}
+		}
+		d.depth--
+		return NewKeyValueList(list), nil
+	case TypeInt64Array:
+		n, err := d.count()
+		if err != nil {
+			return Variant{}, err
+		}
+		arr := make([]int64, n)
+		for i := range arr {
+			x, l := binary.Varint(d.data)
+			if l <= 0 {
+				return Variant{}, errBinaryTruncated
+			}
+			d.data = d.data[l:]
+			arr[i] = x
+		}
+		return NewInt64Array(arr), nil
+	case TypeFloat64Array:
+		n, err := d.count()
+		if err != nil {
+			return Variant{}, err
+		}
+		arr := make([]float64, n)
+		for i := range arr {
+			if len(d.data) < 8 {
+				return Variant{}, errBinaryTruncated
+			}
+			arr[i] = math.Float64frombits(binary.LittleEndian.Uint64(d.data))
+			d.data = d.data[8:]
+		}
+		return NewFloat64Array(arr), nil
+	case TypeStringArray:
+		n, err := d.count()
+		if err != nil {
+			return Variant{}, err
+		}
+		arr := make([]string, n)
+		for i := range arr {
+			s, err := d.bytes()
+			if err != nil {
+				return Variant{}, err
+			}
+			arr[i] = string(s)
+		}
+		return NewStringArray(arr), nil
+	}
+	return Variant{}, fmt.Errorf("invalid Variant binary encoding: unknown type %d", t)
+}
+
+// enter increments the depth before decoding the elements of a list.
+func (d *binaryDecoder) enter() error {
+	if d.depth == maxBinaryDepth {
+		return fmt.Errorf("invalid Variant binary encoding: lists nested deeper than %d", maxBinaryDepth)
+	}
+	d.depth++
+	return nil
+}
+
+// count reads the element count of a list. Every element occupies at least one
+// byte, which allows to reject invalid counts before allocating the list.
+func (d *binaryDecoder) count() (int, error) {
+	n, l := binary.Uvarint(d.data)
+	if l <= 0 || n > uint64(len(d.data)-l) {
+		return 0, errBinaryTruncated
+	}
+	d.data = d.data[l:]
+	return int(n), nil
+}
+
+func (d *binaryDecoder) bytes() ([]byte, error) {
+	n, l := binary.Uvarint(d.data)
+	if l <= 0 || n > uint64(len(d.data)-l) {
+		return nil, errBinaryTruncated
+	}
+	s := d.data[l : l+int(n)]
+	d.data = d.data[l+int(n):]
+	return s, nil
+}
@@ -0,0 +1,133 @@
+package variant
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	vals := []Variant{
+		NewEmpty(),
+		NewInt(0),
+		NewInt(-1),
+		NewInt(math.MaxInt32),
+		NewFloat64(2),
+		NewFloat64(math.Inf(-1)),
+		NewString(""),
+		NewString("abc"),
+		NewBytes(nil),
+		NewBytes([]byte{0, 0xFF}),
+		NewValueList(nil),
+		NewValueList([]Variant{NewInt(1), NewString("a"), NewValueList([]Variant{NewEmpty()})}),
+		NewKeyValueList([]KeyValue{
+			{Key: "b", Value: NewBytes([]byte("xyz"))},
+			{Key: "", Value: NewKeyValueList([]KeyValue{{Key: "f", Value: NewFloat64(1.5)}})},
+		}),
+		NewInt64Array(nil),
+		NewInt64Array([]int64{math.MinInt64, 0, math.MaxInt64}),
+		NewFloat64Array([]float64{-0.5, math.Inf(1)}),
+		NewStringArray([]string{"", "abc"}),
+	}
+
+	for _, v := range vals {
+		t.Run(v.String(), func(t *testing.T) {
+			b, err := v.MarshalBinary()
+			require.NoError(t, err)
+
+			var r Variant
+			require.NoError(t, r.UnmarshalBinary(b))
+			assert.EqualValues(t, v.Type(), r.Type())
+			assert.EqualValues(t, v.String(), r.String())
+		})
+	}
+}
+
+func TestBinaryNoAliasing(t *testing.T) {
+	b, err := NewValueList([]Variant{NewString("abc"), NewBytes([]byte("def"))}).MarshalBinary()
+	require.NoError(t, err)
+
+	var r Variant
+	require.NoError(t, r.UnmarshalBinary(b))
+	for i := range b {
+		b[i] = 0
+	}
+	assert.EqualValues(t, `["abc",0x646566]`, r.String())
+}
+
+func TestUnmarshalBinaryInvalid(t *testing.T) {
+	valid, err := NewKeyValueList([]KeyValue{
+		{Key: "k", Value: NewValueList([]Variant{NewString("abc"), NewInt(300), NewFloat64(1)})},
+		{Key: "a", Value: NewValueList([]Variant{
+			NewInt64Array([]int64{300}), NewFloat64Array([]float64{1}), NewStringArray([]string{"abc"}),
+		})},
+	}).MarshalBinary()
+	require.NoError(t, err)
+
+	// Every truncation of valid data must be rejected.
+	for i := 0; i < len(valid); i++ {
+		var v Variant
+		assert.Error(t, v.UnmarshalBinary(valid[:i]), "truncated at %d", i)
+	}
+
+	var v Variant
+	assert.Error(t, v.UnmarshalBinary(append(valid, 0)), "trailing data")
+	assert.Error(t, v.UnmarshalBinary([]byte(binaryMagic+"\x0a")), "unknown type")
+	assert.Error(t, v.UnmarshalBinary([]byte(binaryMagic+"\x05\xff\xff\xff\xff\x0f")), "huge count")
+	assert.Error(t, v.UnmarshalBinary([]byte("[1,2]")), "not binary")
+	assert.EqualValues(t, TypeEmpty, v.Type())
+}
+
+func TestUnmarshalBinaryInt64OutOfRange(t *testing.T) {
+	if strconv.IntSize != 32 {
+		t.Skip("int64 fits into int")
+	}
+	var v Variant
+	data := appendVarint(append([]byte(binaryMagic), byte(TypeInt)), 1<<40)
+	require.NoError(t, v.UnmarshalBinary(data))
+	assert.EqualValues(t, NewFloat64(1<<40), v)
+}
+
+func TestUnmarshalBinaryMaxDepth(t *testing.T) {
+	// nested returns the encoding of n nested lists, where the innermost list is a
+	// KeyValueList that contains an empty ValueList.
+	nested := func(n int) []byte {
+		return []byte(binaryMagic + strings.Repeat("\x05\x01", n-2) + "\x06\x01\x01k\x05\x00")
+	}
+
+	var v Variant
+	require.NoError(t, v.UnmarshalBinary(nested(maxBinaryDepth)))
+	assert.EqualValues(t, maxBinaryDepth, depthOf(v))
+
+	assert.EqualError(
+		t, v.UnmarshalBinary(nested(maxBinaryDepth+1)),
+		"invalid Variant binary encoding: lists nested deeper than 10000",
+	)
+	assert.EqualError(
+		t, v.UnmarshalBinary([]byte(binaryMagic+strings.Repeat("\x05\x01", 2*maxBinaryDepth))),
+		"invalid Variant binary encoding: lists nested deeper than 10000",
+	)
+}
+
+// depthOf returns the number of nested lists in v.
+func depthOf(v Variant) int {
+	n := 0
+	for {
+		switch v.Type() {
+		case TypeValueList:
+			if v.Len() == 0 {
+				return n + 1
+			}
+			v = v.ValueAt(0)
+		case TypeKeyValueList:
+			v = v.KeyValueAt(0).Value
+		default:
+			return n
+		}
+		n++
+	}
+}
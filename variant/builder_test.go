@@ -0,0 +1,172 @@
+package variant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The slices are allocated once, so that buildTestValue allocates only the lists.
+var (
+	builderTestBytes = []byte{0xFF}
+	builderTestArray = NewInt64Array([]int64{1})
+)
+
+func buildTestValue(b *Builder) {
+	b.BeginMap()
+	b.Key("a")
+	b.BeginList()
+	b.Int(1)
+	b.Float64(2.5)
+	b.String("x")
+	b.Bytes(builderTestBytes)
+	b.Empty()
+	b.BeginList()
+	b.End()
+	b.End()
+	b.Key("b")
+	b.BeginMap()
+	b.Key("c")
+	b.Value(builderTestArray)
+	b.End()
+	b.End()
+}
+
+func TestBuilder(t *testing.T) {
+	var b Builder
+	buildTestValue(&b)
+	v := b.Result()
+	assert.EqualValues(t, `{"a":[1,2.5,"x",0xFF,,[]],"b":{"c":int64[1]}}`, v.String())
+	require.NoError(t, v.Validate())
+
+	// The lists are exactly sized.
+	list := v.KeyValueList()[0].Value.ValueList()
+	assert.EqualValues(t, 6, len(list))
+	assert.EqualValues(t, 6, cap(list))
+
+	// The Builder is reusable after Result.
+	b.Int(5)
+	assert.EqualValues(t, "5", b.Result().String())
+
+	// The buffers are reused.
+	allocs := testing.AllocsPerRun(10, func() {
+		b.BeginList()
+		b.Int(1)
+		b.End()
+		b.Result()
+	})
+	assert.EqualValues(t, 1, allocs)
+}
+
+func TestBuilderArena(t *testing.T) {
+	b := Builder{Arena: &Arena{}}
+	buildTestValue(&b)
+	v := b.Result()
+	assert.EqualValues(t, `{"a":[1,2.5,"x",0xFF,,[]],"b":{"c":int64[1]}}`, v.String())
+
+	allocs := testing.AllocsPerRun(10, func() {
+		buildTestValue(&b)
+		b.Result()
+		b.Arena.Reset()
+	})
+	assert.EqualValues(t, 0, allocs)
+}
+
+func TestBuilderErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		calls func(b *Builder)
+		err   string
+	}{
+		{
+			"end without begin",
+			func(b *Builder) { b.End() },
+			"invalid Builder.End call: no list is open",
+		},
+		{
+			"key outside of map",
+			func(b *Builder) { b.BeginList(); b.Key("a") },
+			"invalid Builder.Key call: not in a map",
+		},
+		{
+			"key at top level",
+			func(b *Builder) { b.Key("a") },
+			"invalid Builder.Key call: not in a map",
+		},
+		{
+			"two keys",
+			func(b *Builder) { b.BeginMap(); b.Key("a"); b.Key("b") },
+			"invalid Builder.Key call: the previous key has no value",
+		},
+		{
+			"value without key",
+			func(b *Builder) { b.BeginMap(); b.Int(1) },
+			"invalid Builder.Int call: a value in a map must be preceded by Key",
+		},
+		{
+			"list without key",
+			func(b *Builder) { b.BeginMap(); b.BeginList() },
+			"invalid Builder.BeginList call: a value in a map must be preceded by Key",
+		},
+		{
+			"end after key",
+			func(b *Builder) { b.BeginMap(); b.Key("a"); b.End() },
+			`invalid Builder.End call: key "a" has no value`,
+		},
+		{
+			"two top-level values",
+			func(b *Builder) { b.String("a"); b.BeginMap() },
+			"invalid Builder.BeginMap call: the top-level value is complete",
+		},
+		{
+			"no value",
+			func(b *Builder) { b.Result() },
+			"invalid Builder.Result call: no value was added",
+		},
+		{
+			"list not ended",
+			func(b *Builder) { b.BeginList(); b.Result() },
+			"invalid Builder.Result call: a list is not ended",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var b Builder
+			assert.PanicsWithError(t, test.err, func() { test.calls(&b) })
+
+			// The Builder can be used after Reset.
+			b.Reset()
+			b.BeginMap()
+			b.Key("k")
+			b.Int(1)
+			b.End()
+			assert.EqualValues(t, `{"k":1}`, b.Result().String())
+		})
+	}
+
+	var err error
+	func() {
+		defer Recover(&err)
+		var b Builder
+		b.End()
+	}()
+	assert.EqualValues(t, &BuilderError{Method: "End", Reason: "no list is open"}, err)
+}
+
+func BenchmarkBuilder(b *testing.B) {
+	var builder Builder
+	for i := 0; i < b.N; i++ {
+		buildTestValue(&builder)
+		builder.Result()
+	}
+}
+
+func BenchmarkBuilderArena(b *testing.B) {
+	builder := Builder{Arena: &Arena{}}
+	for i := 0; i < b.N; i++ {
+		buildTestValue(&builder)
+		builder.Result()
+		builder.Arena.Reset()
+	}
+}
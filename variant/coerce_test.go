@@ -0,0 +1,180 @@
+package variant
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsInt(t *testing.T) {
+	maxInt := int(^uint(0) >> 1)
+	tests := []struct {
+		v       Variant
+		lenient interface{}
+		strict  interface{}
+	}{
+		// The expected value is an int, or nil if an error is expected.
+		{NewInt(-5), -5, -5},
+		{NewFloat64(3), 3, 3},
+		{NewFloat64(-3), -3, -3},
+		{NewFloat64(3.5), nil, nil},
+		{NewFloat64(math.NaN()), nil, nil},
+		{NewFloat64(math.Inf(1)), nil, nil},
+		{NewFloat64(1e100), nil, nil},
+		{NewString("42"), 42, nil},
+		{NewString("-42"), -42, nil},
+		{NewString("1e3"), 1000, nil},
+		{NewString("1.5"), nil, nil},
+		{NewString(strconv.Itoa(maxInt)), maxInt, nil},
+		{NewString(strconv.Itoa(maxInt) + "0"), nil, nil},
+		{NewString(" 1"), nil, nil},
+		{NewString("abc"), nil, nil},
+		{NewEmpty(), nil, nil},
+		{NewBytes([]byte("1")), nil, nil},
+		{NewValueList(nil), nil, nil},
+		{NewKeyValueList(nil), nil, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.v.String(), func(t *testing.T) {
+			for _, c := range []struct {
+				coercer  Coercer
+				expected interface{}
+			}{{Coercer{}, test.lenient}, {Coercer{Strict: true}, test.strict}} {
+				i, err := c.coercer.AsInt(test.v)
+				if c.expected == nil {
+					assert.Error(t, err)
+					assert.EqualValues(t, 0, i)
+				} else {
+					assert.NoError(t, err)
+					assert.EqualValues(t, c.expected, i)
+				}
+			}
+		})
+	}
+
+	// The methods of Variant use the lenient rules.
+	v := NewString("7")
+	i, err := v.AsInt()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 7, i)
+}
+
+func TestAsIntTruncate(t *testing.T) {
+	c := Coercer{Truncate: true}
+	i, err := c.AsInt(NewFloat64(3.9))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, i)
+
+	i, err = c.AsInt(NewFloat64(-3.9))
+	assert.NoError(t, err)
+	assert.EqualValues(t, -3, i)
+
+	i, err = c.AsInt(NewString("2.5"))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, i)
+
+	// Out of range values are an error even with Truncate.
+	_, err = c.AsInt(NewFloat64(1e100))
+	assert.Error(t, err)
+}
+
+func TestAsFloat64(t *testing.T) {
+	v := NewInt(-5)
+	f, err := v.AsFloat64()
+	assert.NoError(t, err)
+	assert.EqualValues(t, -5, f)
+
+	v = NewFloat64(1.5)
+	f, err = v.AsFloat64()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1.5, f)
+
+	v = NewString("1.25e2")
+	f, err = v.AsFloat64()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 125, f)
+
+	_, err = Coercer{Strict: true}.AsFloat64(v)
+	assert.Error(t, err)
+
+	for _, v := range []Variant{NewString("abc"), NewString("1e400"), NewEmpty(), NewBytes(nil)} {
+		_, err = v.AsFloat64()
+		assert.Error(t, err, v.String())
+	}
+}
+
+func TestAsString(t *testing.T) {
+	tests := []struct {
+		v        Variant
+		expected string
+	}{
+		{NewEmpty(), ""},
+		{NewInt(12), "12"},
+		{NewFloat64(0.5), "0.5"},
+		{NewString("abc"), "abc"},
+		{NewString("a longer string"), "a longer string"},
+		{NewBytes([]byte{1, 0xAB}), "0x01AB"},
+		{NewValueList([]Variant{NewInt(1), NewString("a")}), `[1,"a"]`},
+	}
+	for _, test := range tests {
+		s, err := test.v.AsString()
+		assert.NoError(t, err)
+		assert.EqualValues(t, test.expected, s)
+
+		s, err = Coercer{Strict: true}.AsString(test.v)
+		if test.v.Type() == TypeString {
+			assert.NoError(t, err)
+			assert.EqualValues(t, test.expected, s)
+		} else {
+			assert.Error(t, err)
+		}
+	}
+
+	// The returned string does not change when the Variant is modified.
+	v := NewString("abc")
+	s, _ := v.AsString()
+	v = NewString("xyz")
+	assert.EqualValues(t, "abc", s)
+}
+
+func TestAsBool(t *testing.T) {
+	tests := []struct {
+		v       Variant
+		lenient interface{}
+		strict  interface{}
+	}{
+		// The expected value is a bool, or nil if an error is expected.
+		{NewInt(0), false, false},
+		{NewInt(1), true, true},
+		{NewInt(2), true, nil},
+		{NewFloat64(0), false, false},
+		{NewFloat64(1), true, true},
+		{NewFloat64(0.5), true, nil},
+		{NewString("true"), true, nil},
+		{NewString("F"), false, nil},
+		{NewString("0"), false, nil},
+		{NewString("yes"), nil, nil},
+		{NewEmpty(), nil, nil},
+		{NewBytes([]byte{1}), nil, nil},
+		{NewValueList(nil), nil, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.v.String(), func(t *testing.T) {
+			for _, c := range []struct {
+				coercer  Coercer
+				expected interface{}
+			}{{Coercer{}, test.lenient}, {Coercer{Strict: true}, test.strict}} {
+				b, err := c.coercer.AsBool(test.v)
+				if c.expected == nil {
+					assert.Error(t, err)
+					assert.False(t, b)
+				} else {
+					assert.NoError(t, err)
+					assert.EqualValues(t, c.expected, b)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,280 @@
+/*
+Package csvv implements reading and writing of CSV data as Variant records.
+
+Each CSV row is represented by a TypeKeyValueList record, where the keys are
+the column names from the header row and the values are the cells of the row.
+A list of records is represented by a TypeValueList.
+
+Reader infers the type of each cell: an empty cell is TypeEmpty, a decimal
+integer that fits into int is TypeInt, a decimal number with a fraction or an
+exponent (or an integer that does not fit into int) is TypeFloat64 and any other
+cell is TypeString.
+
+Writer writes TypeEmpty as an empty cell, TypeInt and TypeFloat64 as decimal
+numbers (float64 values without a fraction are written with ".0" suffix, so that
+they are read back as TypeFloat64), TypeString as is and TypeBytes as the string
+that contains the bytes. Lists cannot be written in CSV cells.
+*/
+package csvv
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/tigrannajaryan/govariant/variant"
+)
+
+// Reader reads CSV rows as records. The first row of the input is the header
+// that contains the column names.
+type Reader struct {
+	// ReuseRecord controls whether calls to Read may return a record that shares
+	// the backing KeyValue slice of the record returned by the previous call, for
+	// performance. By default each call to Read returns a newly allocated record.
+	//
+	// When ReuseRecord is true the record returned by Read is only valid until
+	// the next call to Read. The strings stored in the record are not affected
+	// and remain valid.
+	ReuseRecord bool
+
+	r      *csv.Reader
+	header []string
+	record []variant.KeyValue
+}
+
+// NewReader returns a new Reader that reads from r. r can be configured (e.g.
+// r.Comma can be set) before the first call to Read.
+func NewReader(r *csv.Reader) *Reader {
+	return &Reader{r: r}
+}
+
+// Header returns the column names. Reads the header row if it was not read yet.
+func (r *Reader) Header() ([]string, error) {
+	if r.header == nil {
+		header, err := r.r.Read()
+		if err != nil {
+			return nil, err
+		}
+		// Make sure the header is not overwritten if csv.Reader reuses records.
+		r.header = append([]string{}, header...)
+	}
+	return r.header, nil
+}
+
+// Read reads one row and returns it as a TypeKeyValueList record. Returns io.EOF
+// if there are no more rows.
+func (r *Reader) Read() (variant.Variant, error) {
+	header, err := r.Header()
+	if err != nil {
+		return variant.Variant{}, err
+	}
+
+	// The csv.Reader checks that the number of fields in each row is the same as
+	// in the header, unless FieldsPerRecord is negative.
+	r.r.ReuseRecord = r.ReuseRecord
+	row, err := r.r.Read()
+	if err != nil {
+		return variant.Variant{}, err
+	}
+	if len(row) > len(header) {
+		return variant.Variant{}, fmt.Errorf("csvv: row has %d fields, header has %d", len(row), len(header))
+	}
+
+	record := r.record
+	if !r.ReuseRecord || cap(record) < len(row) {
+		record = make([]variant.KeyValue, len(row))
+	}
+	record = record[:len(row)]
+	for i, cell := range row {
+		record[i] = variant.KeyValue{Key: header[i], Value: ParseCell(cell)}
+	}
+	if r.ReuseRecord {
+		r.record = record
+	}
+	return variant.NewKeyValueList(record), nil
+}
+
+// ReadAll reads all remaining rows and returns them as a TypeValueList of records.
+// ReuseRecord is ignored, each record has its own storage.
+func (r *Reader) ReadAll() (variant.Variant, error) {
+	reuse := r.ReuseRecord
+	r.ReuseRecord = false
+	defer func() { r.ReuseRecord = reuse }()
+
+	var list []variant.Variant
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return variant.NewValueList(list), nil
+		}
+		if err != nil {
+			return variant.Variant{}, err
+		}
+		list = append(list, record)
+	}
+}
+
+// ParseCell converts the content of a CSV cell to a Variant, inferring the type
+// as described in the package documentation.
+func ParseCell(s string) variant.Variant {
+	if s == "" {
+		return variant.NewEmpty()
+	}
+	if !isNumber(s) {
+		return variant.NewString(s)
+	}
+	if i, err := strconv.ParseInt(s, 10, 0); err == nil {
+		return variant.NewInt(int(i))
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return variant.NewFloat64(f)
+	}
+	return variant.NewString(s)
+}
+
+// isNumber returns true if s contains only the characters that can appear in
+// a decimal number. strconv.ParseFloat also accepts strings such as "inf",
+// "NaN" or hexadecimal numbers, which are not treated as numbers in CSV cells.
+func isNumber(s string) bool {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c >= '0' && c <= '9', c == '+', c == '-', c == '.', c == 'e', c == 'E':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Writer writes records as CSV rows.
+type Writer struct {
+	w           *csv.Writer
+	columns     []string
+	index       map[string]int
+	wroteHeader bool
+	row         []string
+}
+
+// NewWriter returns a new Writer that writes to w.
+func NewWriter(w *csv.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// SetColumns sets the columns to write and their order. Must be called before
+// the first call to Write. If SetColumns is not called the columns are the keys
+// of the first written record.
+func (w *Writer) SetColumns(columns []string) {
+	w.columns = columns
+}
+
+// Write writes a TypeKeyValueList record as a CSV row. The header row is written
+// before the first record. Cells for the columns that are missing in the record
+// are left empty. Returns an error if the record contains a key that is not one
+// of the columns.
+func (w *Writer) Write(record variant.Variant) error {
+	if record.Type() != variant.TypeKeyValueList {
+		return fmt.Errorf("csvv: record must be a KeyValueList, found %v", record.Type())
+	}
+	list := record.KeyValueList()
+
+	if !w.wroteHeader {
+		if w.columns == nil {
+			w.columns = Columns(record)
+		}
+		if err := w.w.Write(w.columns); err != nil {
+			return err
+		}
+		w.wroteHeader = true
+		w.index = make(map[string]int, len(w.columns))
+		for i, c := range w.columns {
+			w.index[c] = i
+		}
+		w.row = make([]string, len(w.columns))
+	}
+
+	for i := range w.row {
+		w.row[i] = ""
+	}
+	for _, kv := range list {
+		i, ok := w.index[kv.Key]
+		if !ok {
+			return fmt.Errorf("csvv: key %q is not one of the columns", kv.Key)
+		}
+		cell, err := FormatCell(kv.Value)
+		if err != nil {
+			return fmt.Errorf("csvv: key %q: %v", kv.Key, err)
+		}
+		w.row[i] = cell
+	}
+	return w.w.Write(w.row)
+}
+
+// WriteAll writes a TypeValueList of records and flushes the output. If columns
+// were not set by SetColumns, the columns are the union of the keys of all records
+// in the order of the first appearance.
+func (w *Writer) WriteAll(records variant.Variant) error {
+	if records.Type() != variant.TypeValueList {
+		return fmt.Errorf("csvv: records must be a ValueList, found %v", records.Type())
+	}
+	list := records.ValueList()
+	if w.columns == nil && !w.wroteHeader {
+		w.columns = Columns(list...)
+	}
+	for _, record := range list {
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// Flush writes any buffered data to the underlying writer.
+func (w *Writer) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// Columns returns the union of the keys of the records in the order of the first
+// appearance. The records that are not TypeKeyValueList are ignored.
+func Columns(records ...variant.Variant) []string {
+	columns := []string{}
+	seen := map[string]bool{}
+	for _, record := range records {
+		if record.Type() != variant.TypeKeyValueList {
+			continue
+		}
+		for _, kv := range record.KeyValueList() {
+			if !seen[kv.Key] {
+				seen[kv.Key] = true
+				columns = append(columns, kv.Key)
+			}
+		}
+	}
+	return columns
+}
+
+// FormatCell converts a Variant to the content of a CSV cell as described in
+// the package documentation. Returns an error for TypeValueList, TypeKeyValueList and
+// the array types.
+func FormatCell(v variant.Variant) (string, error) {
+	switch v.Type() {
+	case variant.TypeEmpty:
+		return "", nil
+	case variant.TypeInt:
+		return strconv.Itoa(v.IntVal()), nil
+	case variant.TypeFloat64:
+		s := strconv.FormatFloat(v.Float64Val(), 'g', -1, 64)
+		if isNumber(s) && !strings.ContainsAny(s, ".e") {
+			s += ".0"
+		}
+		return s, nil
+	case variant.TypeString:
+		return v.StringVal(), nil
+	case variant.TypeBytes:
+		return string(v.Bytes()), nil
+	}
+	return "", fmt.Errorf("%v cannot be written in a CSV cell", v.Type())
+}
@@ -0,0 +1,202 @@
+package csvv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tigrannajaryan/govariant/variant"
+)
+
+func TestParseCell(t *testing.T) {
+	tests := []struct {
+		cell string
+		str  string
+		typ  variant.Type
+	}{
+		{``, ``, variant.TypeEmpty},
+		{`12`, `12`, variant.TypeInt},
+		{`-007`, `-7`, variant.TypeInt},
+		{`+3`, `3`, variant.TypeInt},
+		{`1.5`, `1.5`, variant.TypeFloat64},
+		{`-1e3`, `-1000`, variant.TypeFloat64},
+		{`99999999999999999999`, `1e+20`, variant.TypeFloat64},
+		{`abc`, `"abc"`, variant.TypeString},
+		{` 1`, `" 1"`, variant.TypeString},
+		{`NaN`, `"NaN"`, variant.TypeString},
+		{`inf`, `"inf"`, variant.TypeString},
+		{`0x10`, `"0x10"`, variant.TypeString},
+		{`1_000`, `"1_000"`, variant.TypeString},
+		{`1-2`, `"1-2"`, variant.TypeString},
+		{`e`, `"e"`, variant.TypeString},
+	}
+	for _, test := range tests {
+		v := ParseCell(test.cell)
+		assert.EqualValues(t, test.typ, v.Type(), test.cell)
+		assert.EqualValues(t, test.str, v.String(), test.cell)
+	}
+}
+
+func TestReader(t *testing.T) {
+	data := "name,age,score\nalice,30,1.5\nbob,,abc\n"
+
+	r := NewReader(csv.NewReader(strings.NewReader(data)))
+	header, err := r.Header()
+	require.NoError(t, err)
+	assert.EqualValues(t, []string{"name", "age", "score"}, header)
+
+	v, err := r.Read()
+	require.NoError(t, err)
+	assert.EqualValues(t, `{"name":"alice","age":30,"score":1.5}`, v.String())
+
+	v, err = r.Read()
+	require.NoError(t, err)
+	assert.EqualValues(t, `{"name":"bob","age":,"score":"abc"}`, v.String())
+
+	_, err = r.Read()
+	assert.Equal(t, io.EOF, err)
+
+	r = NewReader(csv.NewReader(strings.NewReader(data)))
+	v, err = r.ReadAll()
+	require.NoError(t, err)
+	assert.EqualValues(t, `[{"name":"alice","age":30,"score":1.5},{"name":"bob","age":,"score":"abc"}]`, v.String())
+
+	r = NewReader(csv.NewReader(strings.NewReader("")))
+	_, err = r.Read()
+	assert.Equal(t, io.EOF, err)
+
+	r = NewReader(csv.NewReader(strings.NewReader("a,b\n1,2,3\n")))
+	_, err = r.Read()
+	assert.Error(t, err)
+
+	cr := csv.NewReader(strings.NewReader("a,b\n1\n1,2,3\n"))
+	cr.FieldsPerRecord = -1
+	r = NewReader(cr)
+	v, err = r.Read()
+	require.NoError(t, err)
+	assert.EqualValues(t, `{"a":1}`, v.String())
+	_, err = r.Read()
+	assert.Error(t, err)
+}
+
+func TestReaderReuseRecord(t *testing.T) {
+	data := "a,b\n1,x\n2,y\n"
+
+	r := NewReader(csv.NewReader(strings.NewReader(data)))
+	v1, err := r.Read()
+	require.NoError(t, err)
+	v2, err := r.Read()
+	require.NoError(t, err)
+	assert.EqualValues(t, `{"a":1,"b":"x"}`, v1.String())
+	assert.EqualValues(t, `{"a":2,"b":"y"}`, v2.String())
+
+	r = NewReader(csv.NewReader(strings.NewReader(data)))
+	r.ReuseRecord = true
+	v1, err = r.Read()
+	require.NoError(t, err)
+	s := v1.KeyValueAt(1).Value.StringVal()
+	v2, err = r.Read()
+	require.NoError(t, err)
+
+	// Both records share the storage.
+	assert.EqualValues(t, `{"a":2,"b":"y"}`, v1.String())
+	assert.EqualValues(t, `{"a":2,"b":"y"}`, v2.String())
+	assert.True(t, &v1.KeyValueList()[0] == &v2.KeyValueList()[0])
+
+	// Strings remain valid.
+	assert.EqualValues(t, "x", s)
+
+	// ReadAll does not reuse the records.
+	r = NewReader(csv.NewReader(strings.NewReader(data)))
+	r.ReuseRecord = true
+	v, err := r.ReadAll()
+	require.NoError(t, err)
+	assert.EqualValues(t, `[{"a":1,"b":"x"},{"a":2,"b":"y"}]`, v.String())
+	assert.True(t, r.ReuseRecord)
+}
+
+func TestWriter(t *testing.T) {
+	records := variant.NewValueList([]variant.Variant{
+		variant.NewKeyValueList([]variant.KeyValue{
+			{Key: "name", Value: variant.NewString("alice, \"al\"")},
+			{Key: "age", Value: variant.NewInt(30)},
+		}),
+		variant.NewKeyValueList([]variant.KeyValue{
+			{Key: "score", Value: variant.NewFloat64(2)},
+			{Key: "name", Value: variant.NewBytes([]byte("bob"))},
+			{Key: "note", Value: variant.NewEmpty()},
+		}),
+		variant.NewKeyValueList(nil),
+	})
+
+	var buf bytes.Buffer
+	w := NewWriter(csv.NewWriter(&buf))
+	require.NoError(t, w.WriteAll(records))
+	assert.EqualValues(t, "name,age,score,note\n\"alice, \"\"al\"\"\",30,,\nbob,,2.0,\n,,,\n", buf.String())
+
+	// Read back.
+	v, err := NewReader(csv.NewReader(&buf)).ReadAll()
+	require.NoError(t, err)
+	assert.EqualValues(
+		t,
+		`[{"name":"alice, \"al\"","age":30,"score":,"note":},{"name":"bob","age":,"score":2,"note":},`+
+			`{"name":,"age":,"score":,"note":}]`,
+		v.String(),
+	)
+}
+
+func TestWriterColumns(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(csv.NewWriter(&buf))
+	w.SetColumns([]string{"b", "a"})
+	require.NoError(t, w.Write(variant.NewKeyValueList([]variant.KeyValue{{Key: "a", Value: variant.NewInt(1)}})))
+	assert.Error(t, w.Write(variant.NewKeyValueList([]variant.KeyValue{{Key: "c", Value: variant.NewInt(1)}})))
+	require.NoError(t, w.Flush())
+	assert.EqualValues(t, "b,a\n,1\n", buf.String())
+
+	// Without SetColumns the columns are taken from the first record.
+	buf.Reset()
+	w = NewWriter(csv.NewWriter(&buf))
+	require.NoError(t, w.Write(variant.NewKeyValueList([]variant.KeyValue{{Key: "x", Value: variant.NewInt(1)}})))
+	assert.Error(t, w.Write(variant.NewKeyValueList([]variant.KeyValue{{Key: "y", Value: variant.NewInt(1)}})))
+	require.NoError(t, w.Flush())
+	assert.EqualValues(t, "x\n1\n", buf.String())
+}
+
+func TestWriterErrors(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(csv.NewWriter(&buf))
+	assert.Error(t, w.Write(variant.NewInt(1)))
+	assert.Error(t, w.WriteAll(variant.NewInt(1)))
+	assert.Error(t, w.WriteAll(variant.NewValueList([]variant.Variant{variant.NewInt(1)})))
+	assert.Error(t, w.Write(variant.NewKeyValueList([]variant.KeyValue{{Key: "a", Value: variant.NewValueList(nil)}})))
+}
+
+func TestFormatCell(t *testing.T) {
+	tests := []struct {
+		v    variant.Variant
+		cell string
+	}{
+		{variant.NewEmpty(), ""},
+		{variant.NewInt(-1), "-1"},
+		{variant.NewFloat64(0.5), "0.5"},
+		{variant.NewFloat64(1e21), "1e+21"},
+		{variant.NewFloat64(-3), "-3.0"},
+		{variant.NewFloat64(math.Inf(1)), "+Inf"},
+		{variant.NewString("s"), "s"},
+	}
+	for _, test := range tests {
+		cell, err := FormatCell(test.v)
+		require.NoError(t, err)
+		assert.EqualValues(t, test.cell, cell)
+	}
+
+	_, err := FormatCell(variant.NewKeyValueList(nil))
+	assert.Error(t, err)
+}
@@ -0,0 +1,27 @@
+// +build variantdebug
+
+package variant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugCheck(t *testing.T) {
+	v := Variant{lenAndType: 10}
+	assert.PanicsWithError(t, "invalid Variant: invalid type 10", func() { v.Type() })
+	assert.Panics(t, func() { v.Len() })
+	assert.Panics(t, func() { _ = v.String() })
+	assert.Panics(t, func() { v.AsInt() })
+
+	// Recover does not stop the panics of corrupted Variants.
+	assert.Panics(t, func() {
+		var err error
+		defer Recover(&err)
+		v.IntVal()
+	})
+
+	v = NewString("abc")
+	assert.NotPanics(t, func() { v.StringVal() })
+}
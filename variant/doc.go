@@ -9,10 +9,11 @@ Variant allows to store values of one of the following types:
  - []byte slice,
  - ordered list of Variant,
  - ordered key/value list of Variant, where key is a string.
+ - packed array of int64, float64 or string.
  - empty or no value.
 
 Variant implementation is optimized for performance: for minimal CPU and
-memory usage. The implementation currently targets amd64 or 386 GOARCH
+memory usage. The implementation currently targets amd64, arm64 or 386 GOARCH
 only (it can be extended to other architectures).
 
 Variant is significantly faster than a typical interface-based implementation. See
@@ -33,6 +34,11 @@ On 64 bit systems the size of Variant is 24 bytes for primitive types such as in
 float64. For variable-sized types (String, List, etc) Variant stores a pointer to the
 data and length counter (similarly to how Go's built-in string and slice types do).
 
+The length counter shares a field with the 4 bit type, so the maximum length of strings,
+byte slices, lists and arrays is maxint/16: 2^59-1 on 64 bit systems and 2^27-1 (about
+134 million) on 32 bit systems. The constructors panic with *LengthError for longer
+values.
+
 To maximize the performance Variant functions do not return errors. All functions define
 clear contracts that describe in which case the calls are valid. In such cases it is
 guaranteed that no errors occur. If the caller violates the contract and it results
@@ -42,5 +48,17 @@ The documentation for each function will describe which of those two things will
 If a list is stored in the Variant it uses panics to mimic the behavior of builtin Go
 slice type. For example accessing an element of a TypeValueList using an index that is
 out of bounds will result in a panic.
+
+The panic values are errors of type *TypeMismatchError, *IndexError or *LengthError.
+Use Recover in a deferred call to convert such panics to an error, e.g. at API
+boundaries where a panic is not desirable. Error and panic messages refer to the types
+by the names returned by Type.String, e.g. "Int" for TypeInt.
+
+Validate checks that a Variant and the values that it contains are not corrupted. If the
+package is built with the variantdebug build tag all methods of Variant check the Variant
+they are called for and panic if it is corrupted, which helps to find the cause of
+crashes in the code that uses unsafe memory operations. Without the tag the functions
+that find a Variant of an invalid type panic with *InvalidTypeError, which Recover does
+not stop.
 */
 package variant
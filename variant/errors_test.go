@@ -0,0 +1,116 @@
+package variant
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recoverErr calls f and returns the error that Recover stores if f panics.
+func recoverErr(f func()) (err error) {
+	defer Recover(&err)
+	f()
+	return nil
+}
+
+func TestPanicErrors(t *testing.T) {
+	v := NewInt(123)
+	err := recoverErr(func() { v.StringVal() })
+	assert.EqualValues(t, &TypeMismatchError{Want: TypeString, Got: TypeInt}, err)
+	assert.EqualValues(t, "Variant type is Int, want String", err.Error())
+
+	err = recoverErr(func() { v.Bytes() })
+	assert.EqualValues(t, &TypeMismatchError{Want: TypeBytes, Got: TypeInt}, err)
+	err = recoverErr(func() { v.ValueList() })
+	assert.EqualValues(t, &TypeMismatchError{Want: TypeValueList, Got: TypeInt}, err)
+	err = recoverErr(func() { v.ValueAt(0) })
+	assert.EqualValues(t, &TypeMismatchError{Want: TypeValueList, Got: TypeInt}, err)
+	err = recoverErr(func() { v.KeyValueList() })
+	assert.EqualValues(t, &TypeMismatchError{Want: TypeKeyValueList, Got: TypeInt}, err)
+	err = recoverErr(func() { v.KeyValueAt(0) })
+	assert.EqualValues(t, &TypeMismatchError{Want: TypeKeyValueList, Got: TypeInt}, err)
+
+	err = recoverErr(func() { v.Resize(0) })
+	assert.EqualValues(t, &TypeMismatchError{Want: AnySliceType, Got: TypeInt}, err)
+	assert.EqualValues(t, "Variant type is Int, want a slice-based type", err.Error())
+
+	v = NewValueList([]Variant{NewInt(1), NewInt(2)})
+	for _, i := range []int{-1, 2} {
+		err = recoverErr(func() { v.ValueAt(i) })
+		assert.EqualValues(t, &IndexError{Index: i, Len: 2}, err)
+	}
+	assert.EqualValues(t, "index 2 out of bounds [0:2]", err.Error())
+
+	v = NewValueList(nil)
+	err = recoverErr(func() { v.ValueAt(0) })
+	assert.EqualValues(t, &IndexError{Index: 0, Len: 0}, err)
+
+	v = NewKeyValueList([]KeyValue{{Key: "k"}})
+	err = recoverErr(func() { v.KeyValueAt(1) })
+	assert.EqualValues(t, &IndexError{Index: 1, Len: 1}, err)
+
+	v = NewBytes(make([]byte, 1, 3))
+	err = recoverErr(func() { v.Resize(4) })
+	assert.EqualValues(t, &LengthError{Len: 4, Max: 3}, err)
+	assert.EqualValues(t, "len 4 exceeds maximum 3", err.Error())
+
+	err = recoverErr(func() { v.Resize(-1) })
+	assert.EqualValues(t, &LengthError{Len: -1, Max: 3}, err)
+	assert.EqualValues(t, "negative len -1 is not allowed", err.Error())
+
+	var typeErr *TypeMismatchError
+	assert.True(t, errors.As(recoverErr(func() { v.StringVal() }), &typeErr))
+	assert.EqualValues(t, TypeBytes, typeErr.Got)
+}
+
+func TestRecover(t *testing.T) {
+	// No panic.
+	assert.NoError(t, recoverErr(func() {}))
+
+	// Other panics are not recovered.
+	assert.PanicsWithValue(t, "other", func() { recoverErr(func() { panic("other") }) })
+
+	otherErr := errors.New("other")
+	assert.PanicsWithValue(t, otherErr, func() { recoverErr(func() { panic(otherErr) }) })
+
+	// Recover keeps the results of the function if there is no panic.
+	f := func(v Variant) (s string, err error) {
+		defer Recover(&err)
+		return v.StringVal(), nil
+	}
+	s, err := f(NewString("abc"))
+	require.NoError(t, err)
+	assert.EqualValues(t, "abc", s)
+
+	_, err = f(NewInt(1))
+	assert.Error(t, err)
+}
+
+func TestInvalidTypeError(t *testing.T) {
+	if debug {
+		t.Skip("the debug checks panic before the type is checked")
+	}
+	v := Variant{lenAndType: 10}
+	tests := []func(){
+		func() { _ = v.String() },
+		func() { _ = v.GoString() },
+		func() { v.MarshalJSON() },
+		func() { v.MarshalBinary() },
+		func() { v.Value() },
+	}
+	for _, f := range tests {
+		assert.PanicsWithError(t, "invalid Variant type 10", f)
+	}
+
+	func() {
+		defer func() {
+			assert.EqualValues(t, &InvalidTypeError{Type: 10}, recover())
+		}()
+		_ = v.String()
+	}()
+
+	// Recover does not stop the panics of corrupted Variants.
+	assert.Panics(t, func() { recoverErr(func() { _ = v.String() }) })
+}
@@ -15,6 +15,97 @@ func ExampleNewInt() {
 	// Output: 123
 }
 
+func ExampleVariant_Int() {
+	v := variant.NewString("123")
+	if i, ok := v.Int(); ok {
+		fmt.Println("int", i)
+	} else if s, ok := v.Str(); ok {
+		fmt.Println("string", s)
+	}
+
+	// Output: string 123
+}
+
+func ExampleCoercer() {
+	v := variant.NewString("123")
+	i, err := v.AsInt()
+	fmt.Println(i, err)
+
+	_, err = variant.Coercer{Strict: true}.AsInt(v)
+	fmt.Println(err)
+
+	v = variant.NewFloat64(2.5)
+	_, err = v.AsInt()
+	fmt.Println(err)
+
+	// Output:
+	// 123 <nil>
+	// cannot convert String to int
+	// cannot convert float64 value 2.5 to int: value has a fractional part
+}
+
+func ExamplePrinter() {
+	v := variant.NewKeyValueList([]variant.KeyValue{
+		{Key: "name", Value: variant.NewString("a very long name")},
+		{Key: "ids", Value: variant.NewValueList([]variant.Variant{
+			variant.NewInt(1), variant.NewInt(2), variant.NewInt(3), variant.NewInt(4),
+		})},
+	})
+
+	p := variant.Printer{Indent: "  ", MaxElems: 2, MaxStringLen: 6, SortKeys: true}
+	fmt.Println(p.Sprint(v))
+	fmt.Printf("%v\n", v)
+
+	// Output:
+	// {
+	//   "ids": [
+	//     1,
+	//     2,
+	//     …(2 more)
+	//   ],
+	//   "name": "a very"…(10 more)
+	// }
+	// {"name":"a very long name","ids":[1,2,3,4]}
+}
+
+func ExampleVariant_GoString() {
+	v := variant.NewKeyValueList([]variant.KeyValue{
+		{Key: "a", Value: variant.NewInt(1)},
+		{Key: "b", Value: variant.NewValueList([]variant.Variant{variant.NewString("x")})},
+	})
+	fmt.Printf("%#v\n", v)
+
+	// Output:
+	// variant.NewKeyValueList([]variant.KeyValue{{Key: "a", Value: variant.NewInt(1)}, {Key: "b", Value: variant.NewValueList([]variant.Variant{variant.NewString("x")})}})
+}
+
+func ExampleMustParse() {
+	v := variant.MustParse(`{"a":[1,2.5,0xFF],"b":"text"}`)
+	for _, kv := range v.KeyValueList() {
+		fmt.Println(kv.Key, kv.Value.Type(), kv.Value.String())
+	}
+
+	// Output:
+	// a ValueList [1,2.5,0xFF]
+	// b String "text"
+}
+
+func ExampleWalk() {
+	v := variant.MustParse(`{"user":"alice","auth":{"password":"secret","tokens":["t1","t2"]}}`)
+
+	// Redact all values under the "auth" key.
+	err := variant.Walk(&v, func(path variant.Path, v *variant.Variant) error {
+		if len(path) == 1 && path[0].Key == "auth" {
+			*v = variant.NewString("<redacted>")
+			return variant.SkipChildren
+		}
+		return nil
+	}, nil)
+	fmt.Println(v.String(), err)
+
+	// Output: {"user":"alice","auth":"<redacted>"} <nil>
+}
+
 func ExampleVariant_String() {
 	v := variant.NewBytes([]byte{1, 2, 0xA})
 	fmt.Println(v.String())
@@ -0,0 +1,180 @@
+package variant
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/parser"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	v := NewKeyValueList([]KeyValue{
+		{Key: "b", Value: NewValueList([]Variant{NewInt(1), NewFloat64(2.5), NewEmpty()})},
+		{Key: "a", Value: NewString("hello, world")},
+		{Key: "c", Value: NewBytes([]byte{0xAF, 0xCD, 0x34})},
+		{Key: "d", Value: NewValueList(nil)},
+	})
+
+	tests := []struct {
+		p        Printer
+		expected string
+	}{
+		{
+			Printer{},
+			`{"b":[1,2.5,],"a":"hello, world","c":0xAFCD34,"d":[]}`,
+		},
+		{
+			Printer{Indent: "  "},
+			"{\n" +
+				"  \"b\": [\n" +
+				"    1,\n" +
+				"    2.5,\n" +
+				"    \n" +
+				"  ],\n" +
+				"  \"a\": \"hello, world\",\n" +
+				"  \"c\": 0xAFCD34,\n" +
+				"  \"d\": []\n" +
+				"}",
+		},
+		{
+			Printer{MaxDepth: 1},
+			`{"b":[…(3 more)],"a":"hello, world","c":0xAFCD34,"d":[]}`,
+		},
+		{
+			Printer{MaxDepth: 1, Indent: "\t"},
+			"{\n\t\"b\": […(3 more)],\n\t\"a\": \"hello, world\",\n\t\"c\": 0xAFCD34,\n\t\"d\": []\n}",
+		},
+		{
+			Printer{MaxElems: 2},
+			`{"b":[1,2.5,…(1 more)],"a":"hello, world",…(2 more)}`,
+		},
+		{
+			Printer{MaxStringLen: 2},
+			`{"b":[1,2.5,],"a":"he"…(10 more),"c":0xAFCD…(1 more),"d":[]}`,
+		},
+		{
+			Printer{Bytes: BytesBase64},
+			`{"b":[1,2.5,],"a":"hello, world","c":base64(r800),"d":[]}`,
+		},
+		{
+			Printer{SortKeys: true, MaxElems: 3},
+			`{"a":"hello, world","b":[1,2.5,],"c":0xAFCD34,…(1 more)}`,
+		},
+	}
+	for _, test := range tests {
+		assert.EqualValues(t, test.expected, Format(v, test.p))
+
+		var buf bytes.Buffer
+		assert.NoError(t, test.p.Fprint(&buf, v))
+		assert.EqualValues(t, test.expected, buf.String())
+	}
+
+	var p Printer
+	assert.EqualValues(t, v.String(), p.Sprint(v))
+
+	// Sprint and Fprint can be called for a Printer literal.
+	assert.EqualValues(t, "[\n  1\n]", Printer{Indent: "  "}.Sprint(NewValueList([]Variant{NewInt(1)})))
+	var buf bytes.Buffer
+	assert.NoError(t, Printer{}.Fprint(&buf, NewInt(1)))
+	assert.EqualValues(t, "1", buf.String())
+}
+
+func TestFormatStringLimit(t *testing.T) {
+	p := Printer{MaxStringLen: 4}
+	assert.EqualValues(t, `"abcd"`, p.Sprint(NewString("abcd")))
+	// Strings are not cut in the middle of a rune.
+	assert.EqualValues(t, `"abц"…(2 more)`, p.Sprint(NewString("abцд")))
+	assert.EqualValues(t, `"abc"…(2 more)`, p.Sprint(NewString("abcд")))
+	assert.EqualValues(t, `"ab"…(3 more)`, p.Sprint(NewString("ab€")))
+	assert.EqualValues(t, `""…(3 more)`, Format(NewString("€"), Printer{MaxStringLen: 2}))
+}
+
+func TestFormatSortKeysStable(t *testing.T) {
+	v := NewKeyValueList([]KeyValue{
+		{Key: "b", Value: NewInt(1)},
+		{Key: "a", Value: NewInt(2)},
+		{Key: "b", Value: NewInt(3)},
+		{Key: "a", Value: NewInt(4)},
+	})
+	assert.EqualValues(t, `{"a":2,"a":4,"b":1,"b":3}`, Format(v, Printer{SortKeys: true}))
+	// The Variant is not modified.
+	assert.EqualValues(t, `{"b":1,"a":2,"b":3,"a":4}`, v.String())
+}
+
+func TestFormatter(t *testing.T) {
+	v := NewValueList([]Variant{NewInt(1), NewKeyValueList([]KeyValue{{Key: "k", Value: NewString("s")}})})
+
+	assert.EqualValues(t, `[1,{"k":"s"}]`, fmt.Sprintf("%v", v))
+	assert.EqualValues(t, `[1,{"k":"s"}]`, fmt.Sprintf("%s", v))
+	assert.EqualValues(t, `"[1,{\"k\":\"s\"}]"`, fmt.Sprintf("%q", v))
+	assert.EqualValues(t, "[\n  1,\n  {\n    \"k\": \"s\"\n  }\n]", fmt.Sprintf("%+v", v))
+	assert.EqualValues(
+		t,
+		`variant.NewValueList([]variant.Variant{variant.NewInt(1), `+
+			`variant.NewKeyValueList([]variant.KeyValue{{Key: "k", Value: variant.NewString("s")}})})`,
+		fmt.Sprintf("%#v", v),
+	)
+	assert.EqualValues(t, "%!d(variant.Variant=123)", fmt.Sprintf("%d", NewInt(123)))
+
+	// Width pads the output.
+	assert.EqualValues(t, "  123", fmt.Sprintf("%5v", NewInt(123)))
+	assert.EqualValues(t, `"ф"  `, fmt.Sprintf("%-5v", NewString("ф")))
+
+	// Pointers are formatted the same way.
+	assert.EqualValues(t, "123", fmt.Sprintf("%v", &[]Variant{NewInt(123)}[0]))
+	assert.EqualValues(t, "[123]", fmt.Sprint([]Variant{NewInt(123)}))
+}
+
+func TestGoString(t *testing.T) {
+	tests := []struct {
+		v        Variant
+		expected string
+	}{
+		{NewEmpty(), "variant.NewEmpty()"},
+		{NewInt(-5), "variant.NewInt(-5)"},
+		{NewFloat64(1.5), "variant.NewFloat64(1.5)"},
+		{NewFloat64(1e100), "variant.NewFloat64(1e+100)"},
+		{NewFloat64(math.NaN()), "variant.NewFloat64(math.NaN())"},
+		{NewFloat64(math.Inf(1)), "variant.NewFloat64(math.Inf(1))"},
+		{NewFloat64(math.Inf(-1)), "variant.NewFloat64(math.Inf(-1))"},
+		{NewFloat64(math.Copysign(0, -1)), "variant.NewFloat64(math.Copysign(0, -1))"},
+		{NewString("a\"b\n"), `variant.NewString("a\"b\n")`},
+		{NewBytes(nil), "variant.NewBytes(nil)"},
+		{NewBytes([]byte{}), "variant.NewBytes([]byte{})"},
+		{NewBytes([]byte{0, 0xAB}), "variant.NewBytes([]byte{0x0, 0xab})"},
+		{NewValueList(nil), "variant.NewValueList(nil)"},
+		{NewKeyValueList([]KeyValue{}), "variant.NewKeyValueList([]variant.KeyValue{})"},
+		{
+			NewKeyValueList([]KeyValue{
+				{Key: "a", Value: NewValueList([]Variant{NewInt(1), NewEmpty()})},
+				{Key: "\xff", Value: NewString("\xff")},
+			}),
+			`variant.NewKeyValueList([]variant.KeyValue{` +
+				`{Key: "a", Value: variant.NewValueList([]variant.Variant{variant.NewInt(1), variant.NewEmpty()})}, ` +
+				`{Key: "\xff", Value: variant.NewString("\xff")}})`,
+		},
+	}
+	for _, test := range tests {
+		assert.EqualValues(t, test.expected, test.v.GoString())
+		assert.EqualValues(t, test.expected, fmt.Sprintf("%#v", test.v))
+
+		// The output must be a valid Go expression.
+		_, err := parser.ParseExpr(test.v.GoString())
+		assert.NoError(t, err, test.expected)
+	}
+}
+
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestPrinterFprintError(t *testing.T) {
+	var p Printer
+	assert.EqualError(t, p.Fprint(errWriter{}, NewInt(1)), "write failed")
+}
@@ -0,0 +1,107 @@
+//go:build go1.23
+// +build go1.23
+
+package variant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tigrannajaryan/govariant/internal/testutil"
+)
+
+func TestValues(t *testing.T) {
+	v := MustParse(`[1,"a",[2]]`)
+	var got []string
+	for i, e := range v.Values() {
+		got = append(got, Path{{Index: i}}.String()+e.String())
+	}
+	assert.EqualValues(t, []string{`[0]1`, `[1]"a"`, `[2][2]`}, got)
+
+	// Break stops the iteration.
+	count := 0
+	for range v.Values() {
+		count++
+		break
+	}
+	assert.EqualValues(t, 1, count)
+
+	empty := NewValueList(nil)
+	for range empty.Values() {
+		assert.Fail(t, "empty list must not be iterated")
+	}
+	i := NewInt(1)
+	assert.PanicsWithError(t, "Variant type is Int, want ValueList", func() { i.Values() })
+}
+
+func TestPairsAndKeys(t *testing.T) {
+	v := MustParse(`{"a":1,"b":"x","a":2}`)
+	var got []string
+	for k, val := range v.Pairs() {
+		got = append(got, k+"="+val.String())
+		if k == "b" {
+			break
+		}
+	}
+	assert.EqualValues(t, []string{"a=1", `b="x"`}, got)
+
+	var keys []string
+	for k := range v.Keys() {
+		keys = append(keys, k)
+	}
+	assert.EqualValues(t, []string{"a", "b", "a"}, keys)
+
+	list := NewValueList(nil)
+	assert.Panics(t, func() { list.Pairs() })
+	str := NewString("a")
+	assert.Panics(t, func() { str.Keys() })
+}
+
+func TestAll(t *testing.T) {
+	v := MustParse(`[1,{"a":"x","b":[2]}]`)
+	var got []string
+	for path, e := range v.All() {
+		got = append(got, path.String()+" "+e.String())
+	}
+	assert.EqualValues(
+		t,
+		[]string{
+			` [1,{"a":"x","b":[2]}]`,
+			`[0] 1`,
+			`[1] {"a":"x","b":[2]}`,
+			`[1]["a"] "x"`,
+			`[1]["b"] [2]`,
+			`[1]["b"][0] 2`,
+		},
+		got,
+	)
+
+	got = nil
+	for path := range v.All() {
+		got = append(got, path.String())
+		if len(path) == 2 {
+			break
+		}
+	}
+	assert.EqualValues(t, []string{"", "[0]", "[1]", `[1]["a"]`}, got)
+
+	got = nil
+	s := NewString("a")
+	for path, e := range s.All() {
+		got = append(got, path.String()+e.String())
+	}
+	assert.EqualValues(t, []string{`"a"`}, got)
+}
+
+func BenchmarkVariantValueListValuesAll(b *testing.B) {
+	vv := NewValueList(createVariantStringSlice(testutil.VariantListSize))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, v := range vv.Values() {
+			if v.Len() == 0 {
+				panic("empty string")
+			}
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package variant
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// MarshalJSON implements json.Marshaler interface.
+//
+// TypeEmpty is encoded as null, TypeInt and TypeFloat64 as numbers, TypeString as
+// a string, TypeBytes as a base64-encoded string, TypeValueList as an array and
+// TypeKeyValueList as an object with the keys in the same order as in the list.
+// The array types are encoded as arrays, which are decoded back as TypeValueList.
+// Float64 values that have no fractional part are encoded with ".0" suffix so that
+// they are decoded back as TypeFloat64.
+//
+// Will return an error if the Variant contains a NaN or infinite float64 value,
+// since these cannot be represented in JSON.
+func (v Variant) MarshalJSON() ([]byte, error) {
+	return appendJSON(nil, v)
+}
+
+func appendJSON(b []byte, v Variant) ([]byte, error) {
+	var err error
+	switch v.Type() {
+	case TypeEmpty:
+		return append(b, "null"...), nil
+	case TypeInt:
+		return strconv.AppendInt(b, int64(v.IntVal()), 10), nil
+	case TypeFloat64:
+		return appendJSONFloat(b, v.Float64Val())
+	case TypeString:
+		return appendJSONString(b, v.StringVal()), nil
+	case TypeBytes:
+		src := v.Bytes()
+		b = append(b, '"')
+		n := len(b)
+		b = append(b, make([]byte, base64.StdEncoding.EncodedLen(len(src)))...)
+		base64.StdEncoding.Encode(b[n:], src)
+		return append(b, '"'), nil
+	case TypeValueList:
+		b = append(b, '[')
+		for i, e := range v.ValueList() {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			if b, err = appendJSON(b, e); err != nil {
+				return nil, err
+			}
+		}
+		return append(b, ']'), nil
+	case TypeKeyValueList:
+		b = append(b, '{')
+		for i, kv := range v.KeyValueList() {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = appendJSONString(b, kv.Key)
+			b = append(b, ':')
+			if b, err = appendJSON(b, kv.Value); err != nil {
+				return nil, err
+			}
+		}
+		return append(b, '}'), nil
+	case TypeInt64Array:
+		b = append(b, '[')
+		for i, x := range v.Int64Array() {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = strconv.AppendInt(b, x, 10)
+		}
+		return append(b, ']'), nil
+	case TypeFloat64Array:
+		b = append(b, '[')
+		for i, f := range v.Float64Array() {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			if b, err = appendJSONFloat(b, f); err != nil {
+				return nil, err
+			}
+		}
+		return append(b, ']'), nil
+	case TypeStringArray:
+		b = append(b, '[')
+		for i, s := range v.StringArray() {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = appendJSONString(b, s)
+		}
+		return append(b, ']'), nil
+	}
+	panic(&InvalidTypeError{Type: v.Type()})
+}
+
+func appendJSONFloat(b []byte, f float64) ([]byte, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("unsupported float64 value in JSON: %v", f)
+	}
+	n := len(b)
+	b = strconv.AppendFloat(b, f, 'g', -1, 64)
+	if bytes.IndexAny(b[n:], ".e") < 0 {
+		// Make sure the number is decoded as float64, not as int.
+		b = append(b, ".0"...)
+	}
+	return b, nil
+}
+
+const hexDigits = "0123456789abcdef"
+
+func appendJSONString(b []byte, s string) []byte {
+	b = append(b, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			b = append(b, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				b = append(b, '\\', c)
+			case '\n':
+				b = append(b, '\\', 'n')
+			case '\r':
+				b = append(b, '\\', 'r')
+			case '\t':
+				b = append(b, '\\', 't')
+			default:
+				b = append(b, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			// Invalid UTF-8 is replaced by the replacement character.
+			b = append(b, s[start:i]...)
+			b = append(b, "\ufffd"...)
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	b = append(b, s[start:]...)
+	return append(b, '"')
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+//
+// null is decoded as TypeEmpty, numbers that contain a fraction or an exponent
+// or that do not fit into int are decoded as TypeFloat64, other numbers are
+// decoded as TypeInt. Strings are decoded as TypeString, true and false are
+// decoded as TypeString with value "true" or "false", arrays are decoded as
+// TypeValueList and objects are decoded as TypeKeyValueList preserving the order
+// of the keys.
+func (v *Variant) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	r, err := decodeJSON(dec)
+	if err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("invalid JSON: unexpected data after top-level value")
+	}
+	*v = r
+	return nil
+}
+
+func decodeJSON(dec *json.Decoder) (Variant, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return Variant{}, err
+	}
+	switch tok := tok.(type) {
+	case nil:
+		return NewEmpty(), nil
+	case bool:
+		return NewString(strconv.FormatBool(tok)), nil
+	case string:
+		return NewString(tok), nil
+	case json.Number:
+		return jsonNumber(string(tok))
+	case json.Delim:
+		switch tok {
+		case '[':
+			var list []Variant
+			for dec.More() {
+				e, err := decodeJSON(dec)
+				if err != nil {
+					return Variant{}, err
+				}
+				list = append(list, e)
+			}
+			if _, err := dec.Token(); err != nil {
+				return Variant{}, err
+			}
+			return NewValueList(list), nil
+		case '{':
+			var list []KeyValue
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return Variant{}, err
+				}
+				e, err := decodeJSON(dec)
+				if err != nil {
+					return Variant{}, err
+				}
+				list = append(list, KeyValue{Key: key.(string), Value: e})
+			}
+			if _, err := dec.Token(); err != nil {
+				return Variant{}, err
+			}
+			return NewKeyValueList(list), nil
+		}
+	}
+	return Variant{}, fmt.Errorf("invalid JSON token %v", tok)
+}
+
+func jsonNumber(s string) (Variant, error) {
+	if !strings.ContainsAny(s, ".eE") {
+		if i, err := strconv.ParseInt(s, 10, 0); err == nil {
+			return NewInt(int(i)), nil
+		}
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return Variant{}, err
+	}
+	return NewFloat64(f), nil
+}
@@ -0,0 +1,114 @@
+package variant
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	tests := []struct {
+		v    Variant
+		json string
+	}{
+		{NewEmpty(), `null`},
+		{NewInt(-123), `-123`},
+		{NewFloat64(1.5), `1.5`},
+		{NewFloat64(2), `2.0`},
+		{NewFloat64(1e21), `1e+21`},
+		{NewString("abc"), `"abc"`},
+		{NewString("a\"b\\c\n\t\r\x01"), `"a\"b\\c\n\t\r\u0001"`},
+		{NewString("\xffé"), "\"\ufffdé\""},
+		{NewBytes([]byte{1, 2, 3}), `"AQID"`},
+		{NewValueList(nil), `[]`},
+		{NewValueList([]Variant{NewInt(1), NewString("a"), NewEmpty()}), `[1,"a",null]`},
+		{NewKeyValueList(nil), `{}`},
+		{
+			NewKeyValueList([]KeyValue{
+				{Key: "z", Value: NewInt(1)},
+				{Key: "a", Value: NewValueList([]Variant{NewFloat64(0.5)})},
+			}),
+			`{"z":1,"a":[0.5]}`,
+		},
+		{NewInt64Array(nil), `[]`},
+		{NewInt64Array([]int64{1, math.MinInt64}), `[1,-9223372036854775808]`},
+		{NewFloat64Array([]float64{2, 0.5}), `[2.0,0.5]`},
+		{NewStringArray([]string{"a", "\n"}), `["a","\n"]`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.json, func(t *testing.T) {
+			b, err := test.v.MarshalJSON()
+			require.NoError(t, err)
+			assert.EqualValues(t, test.json, string(b))
+
+			// Must be valid JSON that encoding/json agrees with.
+			assert.True(t, json.Valid(b))
+			b, err = json.Marshal(test.v)
+			require.NoError(t, err)
+			assert.EqualValues(t, test.json, string(b))
+		})
+	}
+
+	_, err := NewFloat64(math.NaN()).MarshalJSON()
+	assert.Error(t, err)
+	_, err = NewValueList([]Variant{NewFloat64(math.Inf(1))}).MarshalJSON()
+	assert.Error(t, err)
+	_, err = NewKeyValueList([]KeyValue{{Key: "a", Value: NewFloat64(math.Inf(-1))}}).MarshalJSON()
+	assert.Error(t, err)
+	_, err = NewFloat64Array([]float64{1, math.NaN()}).MarshalJSON()
+	assert.Error(t, err)
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		json string
+		str  string
+		typ  Type
+	}{
+		{`null`, ``, TypeEmpty},
+		{`123`, `123`, TypeInt},
+		{`-1.5`, `-1.5`, TypeFloat64},
+		{`2.0`, `2`, TypeFloat64},
+		{`1e2`, `100`, TypeFloat64},
+		{`99999999999999999999`, `1e+20`, TypeFloat64},
+		{`true`, `"true"`, TypeString},
+		{`"aA"`, `"aA"`, TypeString},
+		{` [1, "a", [], {}] `, `[1,"a",[],{}]`, TypeValueList},
+		{`{"z":1,"a":{"b":null}}`, `{"z":1,"a":{"b":}}`, TypeKeyValueList},
+	}
+
+	for _, test := range tests {
+		t.Run(test.json, func(t *testing.T) {
+			var v Variant
+			require.NoError(t, v.UnmarshalJSON([]byte(test.json)))
+			assert.EqualValues(t, test.typ, v.Type())
+			assert.EqualValues(t, test.str, v.String())
+		})
+	}
+
+	invalid := []string{``, `[`, `{"a":}`, `[1]]`, `1 2`, `{1:2}`, `1e400`}
+	for _, s := range invalid {
+		var v Variant
+		assert.Error(t, v.UnmarshalJSON([]byte(s)), s)
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	v := NewKeyValueList([]KeyValue{
+		{Key: "int", Value: NewInt(math.MinInt32)},
+		{Key: "float", Value: NewFloat64(3)},
+		{Key: "str", Value: NewString("x y")},
+		{Key: "list", Value: NewValueList([]Variant{NewEmpty(), NewFloat64(-0.25)})},
+	})
+
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	var r Variant
+	require.NoError(t, json.Unmarshal(b, &r))
+	assert.EqualValues(t, v.String(), r.String())
+}
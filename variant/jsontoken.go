@@ -0,0 +1,176 @@
+package variant
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// JSONTokenReader reads the tokens of a stream of JSON values, which are decoded like
+// in Variant.UnmarshalJSON. The values in the stream can be separated by whitespace.
+type JSONTokenReader struct {
+	dec *json.Decoder
+
+	// maps are the open arrays and objects, true for objects.
+	maps []bool
+
+	// expectKey is true if the next token of the innermost object is a key or the
+	// end of the object.
+	expectKey bool
+}
+
+// NewJSONTokenReader creates a JSONTokenReader that reads from r. The reader is
+// buffered, so it can read past the last returned token.
+func NewJSONTokenReader(r io.Reader) *JSONTokenReader {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	return &JSONTokenReader{dec: dec}
+}
+
+// Token implements TokenReader interface.
+func (r *JSONTokenReader) Token() (Token, error) {
+	tok, err := r.dec.Token()
+	if err != nil {
+		return Token{}, err
+	}
+	if r.expectKey {
+		if tok == json.Delim('}') {
+			r.end()
+			return Token{Kind: TokenEndMap}, nil
+		}
+		// The decoder returns an error for the keys that are not strings.
+		r.expectKey = false
+		return Token{Kind: TokenKey, Key: tok.(string)}, nil
+	}
+
+	var v Variant
+	switch tok := tok.(type) {
+	case nil:
+	case bool:
+		v = NewString(strconv.FormatBool(tok))
+	case string:
+		v = NewString(tok)
+	case json.Number:
+		if v, err = jsonNumber(string(tok)); err != nil {
+			return Token{}, err
+		}
+	case json.Delim:
+		switch tok {
+		case '[':
+			r.maps = append(r.maps, false)
+			return Token{Kind: TokenBeginList}, nil
+		case '{':
+			r.maps = append(r.maps, true)
+			r.expectKey = true
+			return Token{Kind: TokenBeginMap}, nil
+		case ']':
+			r.end()
+			return Token{Kind: TokenEndList}, nil
+		}
+		return Token{}, fmt.Errorf("invalid JSON token %v", tok)
+	}
+	r.valueDone()
+	return Token{Kind: TokenValue, Value: v}, nil
+}
+
+// end removes the innermost open array or object, which is a value of its parent.
+func (r *JSONTokenReader) end() {
+	r.maps = r.maps[:len(r.maps)-1]
+	r.valueDone()
+}
+
+// valueDone sets the state after a value of the innermost array or object.
+func (r *JSONTokenReader) valueDone() {
+	r.expectKey = len(r.maps) > 0 && r.maps[len(r.maps)-1]
+}
+
+// jsonFlushSize is the size of the output that JSONTokenWriter buffers before it
+// writes the output within a top-level value.
+const jsonFlushSize = 4096
+
+// JSONTokenWriter writes tokens as a stream of JSON values, which are encoded like in
+// Variant.MarshalJSON. Each top-level value is followed by a newline.
+//
+// The output is buffered and written when a top-level value is complete or the buffer
+// is full, so a value that is not complete may be partially written. The first error
+// is returned by all following WriteToken calls.
+type JSONTokenWriter struct {
+	w   io.Writer
+	buf []byte
+	err error
+
+	checker tokenChecker
+
+	// first is true if the next value of the innermost open list is its first one.
+	first bool
+}
+
+// NewJSONTokenWriter creates a JSONTokenWriter that writes to w.
+func NewJSONTokenWriter(w io.Writer) *JSONTokenWriter {
+	return &JSONTokenWriter{w: w}
+}
+
+// WriteToken implements TokenWriter interface. Will return an error if the token
+// cannot follow the previous tokens or if the value of a TokenValue cannot be
+// encoded, in which case the token is not written.
+func (w *JSONTokenWriter) WriteToken(t Token) error {
+	if w.err != nil {
+		return w.err
+	}
+	n := len(w.buf)
+	// The elements of a list are separated by commas, in a map the comma is written
+	// before the key.
+	if len(w.checker.maps) > 0 && !w.first && (t.Kind == TokenKey) == w.checker.inMap() {
+		switch t.Kind {
+		case TokenKey, TokenValue, TokenBeginList, TokenBeginMap:
+			w.buf = append(w.buf, ',')
+		}
+	}
+	switch t.Kind {
+	case TokenKey:
+		w.buf = appendJSONString(w.buf, t.Key)
+		w.buf = append(w.buf, ':')
+	case TokenBeginList:
+		w.buf = append(w.buf, '[')
+	case TokenBeginMap:
+		w.buf = append(w.buf, '{')
+	case TokenEndList:
+		w.buf = append(w.buf, ']')
+	case TokenEndMap:
+		w.buf = append(w.buf, '}')
+	case TokenValue:
+		b, err := appendJSON(w.buf, t.Value)
+		if err != nil {
+			w.buf = w.buf[:n]
+			return err
+		}
+		w.buf = b
+	}
+	if err := w.checker.check(t); err != nil {
+		w.buf = w.buf[:n]
+		return err
+	}
+
+	switch t.Kind {
+	case TokenKey:
+		return nil
+	case TokenBeginList, TokenBeginMap:
+		w.first = true
+		return nil
+	}
+	return w.valueDone()
+}
+
+// valueDone writes the buffer if a top-level value is complete or the buffer is full.
+func (w *JSONTokenWriter) valueDone() error {
+	w.first = false
+	if len(w.checker.maps) == 0 {
+		w.buf = append(w.buf, '\n')
+	} else if len(w.buf) < jsonFlushSize {
+		return nil
+	}
+	_, w.err = w.w.Write(w.buf)
+	w.buf = w.buf[:0]
+	return w.err
+}
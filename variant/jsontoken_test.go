@@ -0,0 +1,152 @@
+package variant
+
+import (
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONTokenReader(t *testing.T) {
+	r := NewJSONTokenReader(strings.NewReader(`
+		{"a": [1, 2.5, "x", true, null], "b": {}, "": [[]]}
+		"next" 3
+	`))
+	var tokens []Token
+	for {
+		tok, err := r.Token()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		tokens = append(tokens, tok)
+	}
+	assert.EqualValues(
+		t, tokenStrings([]Token{
+			beginMapToken,
+			keyToken("a"), beginListToken,
+			valueToken(NewInt(1)),
+			valueToken(NewFloat64(2.5)),
+			valueToken(NewString("x")),
+			valueToken(NewString("true")),
+			valueToken(NewEmpty()),
+			endListToken,
+			keyToken("b"), beginMapToken, endMapToken,
+			keyToken(""), beginListToken, beginListToken, endListToken, endListToken,
+			endMapToken,
+			valueToken(NewString("next")),
+			valueToken(NewInt(3)),
+		}), tokenStrings(tokens),
+	)
+}
+
+func TestJSONTokenReaderErrors(t *testing.T) {
+	tests := []string{`[1,]`, `{1:2}`, `{"a" 1}`, `[1}`, `]`}
+	for _, s := range tests {
+		r := NewJSONTokenReader(strings.NewReader(s))
+		var err error
+		for err == nil {
+			_, err = r.Token()
+		}
+		assert.NotEqual(t, io.EOF, err, s)
+	}
+
+	_, err := ReadVariant(NewJSONTokenReader(strings.NewReader(`{"a":[1`)))
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+}
+
+func TestJSONTokenWriter(t *testing.T) {
+	var sb strings.Builder
+	w := NewJSONTokenWriter(&sb)
+	tokens := []Token{
+		beginMapToken,
+		keyToken("a"), beginListToken, valueToken(NewInt(1)), beginMapToken, endMapToken, valueToken(NewString("x")), endListToken,
+		keyToken("b\n"), valueToken(NewFloat64Array([]float64{1, 0.5})),
+		keyToken("c"), beginListToken, beginListToken, endListToken, endListToken,
+		endMapToken,
+		valueToken(NewEmpty()),
+		valueToken(NewValueList([]Variant{NewInt(1), NewInt(2)})),
+	}
+	for _, tok := range tokens {
+		require.NoError(t, w.WriteToken(tok))
+	}
+	assert.EqualValues(
+		t, "{\"a\":[1,{},\"x\"],\"b\\n\":[1.0,0.5],\"c\":[[]]}\nnull\n[1,2]\n", sb.String(),
+	)
+}
+
+func TestJSONTokenWriterErrors(t *testing.T) {
+	var sb strings.Builder
+	w := NewJSONTokenWriter(&sb)
+	require.NoError(t, w.WriteToken(beginListToken))
+	require.NoError(t, w.WriteToken(valueToken(NewInt(1))))
+
+	// The invalid tokens are not written.
+	assert.EqualError(t, w.WriteToken(keyToken("a")), "unexpected Key token: not in a map")
+	assert.EqualError(t, w.WriteToken(endMapToken), "unexpected EndMap token: the innermost list is not a map")
+	assert.EqualError(
+		t, w.WriteToken(valueToken(NewFloat64(math.NaN()))), "unsupported float64 value in JSON: NaN",
+	)
+	require.NoError(t, w.WriteToken(valueToken(NewInt(2))))
+	require.NoError(t, w.WriteToken(endListToken))
+	assert.EqualValues(t, "[1,2]\n", sb.String())
+
+	// Write errors are returned by all following calls.
+	errWriter := errors.New("write failed")
+	w = NewJSONTokenWriter(failingWriter{errWriter})
+	assert.Equal(t, errWriter, w.WriteToken(valueToken(NewInt(1))))
+	assert.Equal(t, errWriter, w.WriteToken(valueToken(NewInt(1))))
+}
+
+type failingWriter struct {
+	err error
+}
+
+func (w failingWriter) Write(p []byte) (int, error) {
+	return 0, w.err
+}
+
+func TestJSONTokenWriterFlush(t *testing.T) {
+	var sb strings.Builder
+	w := NewJSONTokenWriter(&sb)
+	require.NoError(t, w.WriteToken(beginListToken))
+	s := NewString(strings.Repeat("a", jsonFlushSize))
+	require.NoError(t, w.WriteToken(valueToken(s)))
+	// The buffer is written within the value when it is full.
+	assert.EqualValues(t, jsonFlushSize+3, sb.Len())
+	require.NoError(t, w.WriteToken(valueToken(s)))
+	require.NoError(t, w.WriteToken(endListToken))
+	assert.EqualValues(t, "["+s.String()+","+s.String()+"]\n", sb.String())
+}
+
+func TestJSONTokenRoundTrip(t *testing.T) {
+	values := []string{
+		`null`,
+		`-1`,
+		`[1,2.5,"a",[],{}]`,
+		`{"a":{"b":[{"c":null}]},"":"x","d":[[1],[2,[3]]]}`,
+	}
+	var sb strings.Builder
+	w := NewJSONTokenWriter(&sb)
+	for _, s := range values {
+		var v Variant
+		require.NoError(t, v.UnmarshalJSON([]byte(s)))
+		require.NoError(t, WriteVariant(w, v))
+	}
+	assert.EqualValues(t, strings.Join(values, "\n")+"\n", sb.String())
+
+	r := NewJSONTokenReader(strings.NewReader(sb.String()))
+	for _, s := range values {
+		v, err := ReadVariant(r)
+		require.NoError(t, err)
+		b, err := v.MarshalJSON()
+		require.NoError(t, err)
+		assert.EqualValues(t, s, string(b))
+	}
+	_, err := ReadVariant(r)
+	assert.Equal(t, io.EOF, err)
+}
@@ -0,0 +1,133 @@
+package variant
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected Variant
+	}{
+		{"", NewEmpty()},
+		{"  ", NewEmpty()},
+		{"123", NewInt(123)},
+		{"-5", NewInt(-5)},
+		{"+5", NewInt(5)},
+		{"2.5", NewFloat64(2.5)},
+		{"1e+06", NewFloat64(1e6)},
+		{"-1E-3", NewFloat64(-0.001)},
+		{"+Inf", NewFloat64(math.Inf(1))},
+		{"-Inf", NewFloat64(math.Inf(-1))},
+		{`"abc"`, NewString("abc")},
+		{`"a\"b\n\xff"`, NewString("a\"b\n\xff")},
+		{"`raw\\n`", NewString(`raw\n`)},
+		{"0x", NewBytes([]byte{})},
+		{"0xAFcd34", NewBytes([]byte{0xAF, 0xCD, 0x34})},
+		{"base64(r800)", NewBytes([]byte{0xAF, 0xCD, 0x34})},
+		{"[]", NewValueList(nil)},
+		{"[1,,2]", NewValueList([]Variant{NewInt(1), NewEmpty(), NewInt(2)})},
+		{"[1,]", NewValueList([]Variant{NewInt(1), NewEmpty()})},
+		{"{}", NewKeyValueList(nil)},
+		{
+			` { "a" : [ 1 , 2.5 , 0xFF ] , "b":{"c":}} `,
+			NewKeyValueList([]KeyValue{
+				{Key: "a", Value: NewValueList([]Variant{NewInt(1), NewFloat64(2.5), NewBytes([]byte{0xFF})})},
+				{Key: "b", Value: NewKeyValueList([]KeyValue{{Key: "c", Value: NewEmpty()}})},
+			}),
+		},
+	}
+	for _, test := range tests {
+		v, err := Parse(test.s)
+		require.NoError(t, err, test.s)
+		// GoString distinguishes all types and values.
+		assert.EqualValues(t, test.expected.GoString(), v.GoString(), test.s)
+	}
+
+	v, err := Parse("NaN")
+	require.NoError(t, err)
+	assert.True(t, math.IsNaN(v.Float64Val()))
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	v := NewKeyValueList([]KeyValue{
+		{Key: "list", Value: NewValueList([]Variant{NewInt(-1), NewFloat64(0.125), NewString("long string value")})},
+		{Key: "bytes", Value: NewBytes([]byte{0, 1, 0xFE})},
+		{Key: "empty", Value: NewEmpty()},
+		{Key: "kvl", Value: NewKeyValueList([]KeyValue{{Key: "k\t", Value: NewString("é")}})},
+	})
+
+	outputs := []string{
+		v.String(),
+		Format(v, Printer{Indent: "\t", SortKeys: true}),
+		Format(v, Printer{Bytes: BytesBase64}),
+	}
+	for _, s := range outputs {
+		parsed, err := Parse(s)
+		require.NoError(t, err, s)
+		sorted := Printer{SortKeys: true}
+		assert.EqualValues(t, sorted.Sprint(v), sorted.Sprint(parsed), s)
+	}
+
+	parsed, err := Parse(v.String())
+	require.NoError(t, err)
+	assert.EqualValues(t, v.GoString(), parsed.GoString())
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		s   string
+		err string
+	}{
+		{"1 2", "invalid Variant literal at offset 2: unexpected '2' after value"},
+		{"]", "invalid Variant literal at offset 0: unexpected ']' after value"},
+		{"[1", "invalid Variant literal at offset 2: expected ',' or ']', found end of input"},
+		{"[1 2]", "invalid Variant literal at offset 3: expected ',' or ']', found '2'"},
+		{"{1:2}", "invalid Variant literal at offset 1: expected a string key, found '1'"},
+		{`{"a"}`, "invalid Variant literal at offset 4: expected ':' after key, found '}'"},
+		{`{"a":1`, "invalid Variant literal at offset 6: expected ',' or '}', found end of input"},
+		{`"abc`, "invalid Variant literal at offset 0: unterminated string"},
+		{`"a\qb"`, `invalid Variant literal at offset 0: invalid string "a\qb"`},
+		{"0xABC", "invalid Variant literal at offset 0: invalid bytes 0xABC"},
+		{"base64(***)", "invalid Variant literal at offset 0: invalid bytes base64(***)"},
+		{"1.2.3", "invalid Variant literal at offset 0: invalid value 1.2.3"},
+		{"1e400", "invalid Variant literal at offset 0: number 1e400 is out of range"},
+		{"true", "invalid Variant literal at offset 0: invalid value true"},
+		{"[:]", "invalid Variant literal at offset 1: unexpected ':'"},
+		{"99999999999999999999", "invalid Variant literal at offset 0: number 99999999999999999999 is out of range"},
+		{`"a"…(3 more)`, "invalid Variant literal at offset 3: unexpected '…' after value"},
+	}
+	for _, test := range tests {
+		_, err := Parse(test.s)
+		assert.EqualError(t, err, test.err, test.s)
+	}
+}
+
+func TestParseMaxDepth(t *testing.T) {
+	// nested returns 2n+2 nested lists, where every other list is a KeyValueList.
+	nested := func(n int) string {
+		return strings.Repeat(`[{"k":`, n) + "[[]]" + strings.Repeat("}]", n)
+	}
+
+	v, err := Parse(nested(maxParseDepth/2 - 1))
+	require.NoError(t, err)
+	assert.EqualValues(t, maxParseDepth, depthOf(v))
+
+	_, err = Parse(nested(maxParseDepth / 2))
+	assert.EqualError(t, err, "invalid Variant literal at offset 30000: lists nested deeper than 10000")
+
+	// The limit is checked before the end of the input is reached.
+	_, err = Parse(strings.Repeat("[", 8000000))
+	assert.EqualError(t, err, "invalid Variant literal at offset 10000: lists nested deeper than 10000")
+}
+
+func TestMustParse(t *testing.T) {
+	v := MustParse(`{"a":[1,2.5,0xFF]}`)
+	assert.EqualValues(t, `{"a":[1,2.5,0xFF]}`, v.String())
+	assert.PanicsWithError(t, "invalid Variant literal at offset 0: invalid value x", func() { MustParse("x") })
+}
@@ -0,0 +1,80 @@
+//go:build go1.21
+// +build go1.21
+
+package variant
+
+// This file contains integration with log/slog package. It is only compiled
+// by Go versions that include log/slog.
+
+import "log/slog"
+
+// LogValue implements slog.LogValuer interface, which allows to pass a Variant
+// directly to slog loggers as an attribute value.
+//
+// TypeKeyValueList is converted to a slog group. TypeValueList is converted to
+// slog.AnyValue that contains a []any slice with the list elements converted
+// to native Go values (see the package slogv for details). The array types are
+// converted to slog.AnyValue that contains the []int64, []float64 or []string slice.
+func (v Variant) LogValue() slog.Value {
+	switch v.Type() {
+	case TypeEmpty:
+		return slog.Value{}
+	case TypeInt:
+		return slog.IntValue(v.IntVal())
+	case TypeFloat64:
+		return slog.Float64Value(v.Float64Val())
+	case TypeString:
+		return slog.StringValue(v.StringVal())
+	case TypeBytes:
+		return slog.AnyValue(v.Bytes())
+	case TypeValueList, TypeInt64Array, TypeFloat64Array, TypeStringArray:
+		return slog.AnyValue(v.anyValue())
+	case TypeKeyValueList:
+		list := v.KeyValueList()
+		attrs := make([]slog.Attr, len(list))
+		for i, kv := range list {
+			attrs[i] = slog.Attr{Key: kv.Key, Value: kv.Value.LogValue()}
+		}
+		return slog.GroupValue(attrs...)
+	}
+	panic(&InvalidTypeError{Type: v.Type()})
+}
+
+// anyValue converts the Variant to a native Go value. Lists become []any,
+// key/value lists become map[string]any (the order of the keys is lost),
+// arrays become []int64, []float64 or []string, TypeEmpty becomes nil.
+func (v Variant) anyValue() any {
+	switch v.Type() {
+	case TypeEmpty:
+		return nil
+	case TypeInt:
+		return v.IntVal()
+	case TypeFloat64:
+		return v.Float64Val()
+	case TypeString:
+		return v.StringVal()
+	case TypeBytes:
+		return v.Bytes()
+	case TypeValueList:
+		list := v.ValueList()
+		r := make([]any, len(list))
+		for i, e := range list {
+			r[i] = e.anyValue()
+		}
+		return r
+	case TypeKeyValueList:
+		list := v.KeyValueList()
+		r := make(map[string]any, len(list))
+		for _, kv := range list {
+			r[kv.Key] = kv.Value.anyValue()
+		}
+		return r
+	case TypeInt64Array:
+		return v.Int64Array()
+	case TypeFloat64Array:
+		return v.Float64Array()
+	case TypeStringArray:
+		return v.StringArray()
+	}
+	panic(&InvalidTypeError{Type: v.Type()})
+}
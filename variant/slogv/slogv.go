@@ -0,0 +1,133 @@
+//go:build go1.21
+// +build go1.21
+
+/*
+Package slogv implements conversion between Variant and log/slog values.
+
+Variant implements slog.LogValuer interface, so it can be passed directly to slog
+loggers. This package additionally allows to convert slog values and attributes
+back to Variant and KeyValue.
+
+Conversion from Variant to slog.Value:
+
+  - TypeEmpty becomes an empty slog.Value (KindAny with nil value),
+  - TypeInt becomes KindInt64,
+  - TypeFloat64 becomes KindFloat64,
+  - TypeString becomes KindString,
+  - TypeBytes becomes KindAny with []byte value,
+  - TypeValueList becomes KindAny with []any value, the elements of which are
+    converted to native Go values (int, float64, string, []byte, []any,
+    map[string]any or nil),
+  - TypeKeyValueList becomes KindGroup,
+  - TypeInt64Array, TypeFloat64Array and TypeStringArray become KindAny with
+    []int64, []float64 or []string value.
+
+Conversion from slog.Value to Variant:
+
+  - KindInt64 becomes TypeInt if it fits into int, otherwise TypeFloat64,
+  - KindUint64 becomes TypeInt if it fits into int, otherwise TypeFloat64,
+  - KindFloat64 becomes TypeFloat64,
+  - KindString becomes TypeString,
+  - KindBool becomes TypeString with value "true" or "false",
+  - KindDuration becomes TypeInt with the number of nanoseconds if it fits into int,
+    otherwise TypeString formatted using time.Duration.String,
+  - KindTime becomes TypeString formatted as RFC 3339 with nanoseconds,
+  - KindGroup becomes TypeKeyValueList,
+  - KindLogValuer is resolved and the resolved value is converted,
+  - KindAny with nil value becomes TypeEmpty, with Variant value is used as is,
+    with []byte value becomes TypeBytes, with []any value becomes TypeValueList,
+    with []int64, []float64 or []string value becomes the array type,
+    with any other value becomes TypeString formatted using fmt.Sprint.
+*/
+package slogv
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/tigrannajaryan/govariant/variant"
+)
+
+// Value converts a Variant to slog.Value. Equivalent to v.LogValue().
+func Value(v variant.Variant) slog.Value {
+	return v.LogValue()
+}
+
+// Attr converts a KeyValue to slog.Attr.
+func Attr(kv variant.KeyValue) slog.Attr {
+	return slog.Attr{Key: kv.Key, Value: kv.Value.LogValue()}
+}
+
+// FromAttr converts slog.Attr to a KeyValue.
+func FromAttr(a slog.Attr) variant.KeyValue {
+	return variant.KeyValue{Key: a.Key, Value: FromValue(a.Value)}
+}
+
+// FromValue converts slog.Value to a Variant.
+func FromValue(v slog.Value) variant.Variant {
+	switch v.Kind() {
+	case slog.KindInt64:
+		i := v.Int64()
+		if int64(int(i)) != i {
+			return variant.NewFloat64(float64(i))
+		}
+		return variant.NewInt(int(i))
+	case slog.KindUint64:
+		u := v.Uint64()
+		if u > math.MaxInt {
+			return variant.NewFloat64(float64(u))
+		}
+		return variant.NewInt(int(u))
+	case slog.KindFloat64:
+		return variant.NewFloat64(v.Float64())
+	case slog.KindString:
+		return variant.NewString(v.String())
+	case slog.KindBool:
+		return variant.NewString(strconv.FormatBool(v.Bool()))
+	case slog.KindDuration:
+		d := v.Duration()
+		if int64(int(d)) != int64(d) {
+			return variant.NewString(d.String())
+		}
+		return variant.NewInt(int(d))
+	case slog.KindTime:
+		return variant.NewString(v.Time().Format(time.RFC3339Nano))
+	case slog.KindGroup:
+		attrs := v.Group()
+		list := make([]variant.KeyValue, len(attrs))
+		for i, a := range attrs {
+			list[i] = FromAttr(a)
+		}
+		return variant.NewKeyValueList(list)
+	case slog.KindLogValuer:
+		return FromValue(v.Resolve())
+	}
+	return fromAny(v.Any())
+}
+
+func fromAny(a any) variant.Variant {
+	switch a := a.(type) {
+	case nil:
+		return variant.NewEmpty()
+	case variant.Variant:
+		return a
+	case []byte:
+		return variant.NewBytes(a)
+	case []any:
+		list := make([]variant.Variant, len(a))
+		for i, e := range a {
+			list[i] = FromValue(slog.AnyValue(e))
+		}
+		return variant.NewValueList(list)
+	case []int64:
+		return variant.NewInt64Array(a)
+	case []float64:
+		return variant.NewFloat64Array(a)
+	case []string:
+		return variant.NewStringArray(a)
+	}
+	return variant.NewString(fmt.Sprint(a))
+}
@@ -0,0 +1,133 @@
+//go:build go1.21
+// +build go1.21
+
+package slogv
+
+import (
+	"bytes"
+	"log/slog"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tigrannajaryan/govariant/variant"
+)
+
+func TestValue(t *testing.T) {
+	assert.EqualValues(t, slog.KindAny, Value(variant.NewEmpty()).Kind())
+	assert.Nil(t, Value(variant.NewEmpty()).Any())
+	assert.EqualValues(t, 123, Value(variant.NewInt(123)).Int64())
+	assert.EqualValues(t, 1.5, Value(variant.NewFloat64(1.5)).Float64())
+	assert.EqualValues(t, "abc", Value(variant.NewString("abc")).String())
+	assert.EqualValues(t, []byte{1, 2}, Value(variant.NewBytes([]byte{1, 2})).Any())
+	assert.EqualValues(t, []int64{1, 2}, Value(variant.NewInt64Array([]int64{1, 2})).Any())
+	assert.EqualValues(t, []string{"a"}, Value(variant.NewStringArray([]string{"a"})).Any())
+
+	v := Value(
+		variant.NewValueList(
+			[]variant.Variant{
+				variant.NewInt(1),
+				variant.NewString("a"),
+				variant.NewValueList([]variant.Variant{variant.NewEmpty()}),
+				variant.NewKeyValueList([]variant.KeyValue{{Key: "k", Value: variant.NewFloat64(2)}}),
+			},
+		),
+	)
+	assert.EqualValues(t, slog.KindAny, v.Kind())
+	assert.EqualValues(t, []any{1, "a", []any{nil}, map[string]any{"k": 2.0}}, v.Any())
+
+	v = Value(
+		variant.NewKeyValueList(
+			[]variant.KeyValue{
+				{Key: "a", Value: variant.NewInt(1)},
+				{Key: "b", Value: variant.NewKeyValueList([]variant.KeyValue{{Key: "c", Value: variant.NewString("x")}})},
+			},
+		),
+	)
+	assert.EqualValues(t, slog.KindGroup, v.Kind())
+	group := v.Group()
+	assert.Len(t, group, 2)
+	assert.EqualValues(t, "a", group[0].Key)
+	assert.EqualValues(t, 1, group[0].Value.Int64())
+	assert.EqualValues(t, "b", group[1].Key)
+	assert.EqualValues(t, slog.KindGroup, group[1].Value.Kind())
+	assert.EqualValues(t, "x", group[1].Value.Group()[0].Value.String())
+}
+
+func TestFromValue(t *testing.T) {
+	assert.EqualValues(t, variant.NewEmpty(), FromValue(slog.Value{}))
+	assert.EqualValues(t, variant.NewInt(-5), FromValue(slog.IntValue(-5)))
+	assert.EqualValues(t, variant.NewInt(5), FromValue(slog.Uint64Value(5)))
+	assert.EqualValues(t, variant.NewFloat64(math.MaxUint64), FromValue(slog.Uint64Value(math.MaxUint64)))
+	assert.EqualValues(t, variant.NewFloat64(1.25), FromValue(slog.Float64Value(1.25)))
+	assert.EqualValues(t, variant.NewString("abc"), FromValue(slog.StringValue("abc")))
+	assert.EqualValues(t, variant.NewString("true"), FromValue(slog.BoolValue(true)))
+	assert.EqualValues(t, variant.NewInt(int(time.Second)), FromValue(slog.DurationValue(time.Second)))
+
+	// The values that do not fit into int on 32 bit GOARCH are not truncated.
+	v := FromValue(slog.Int64Value(math.MinInt64))
+	d := FromValue(slog.DurationValue(math.MaxInt64))
+	if math.MaxInt == math.MaxInt64 {
+		assert.EqualValues(t, "-9223372036854775808", v.String())
+		assert.EqualValues(t, "9223372036854775807", d.String())
+	} else {
+		assert.EqualValues(t, variant.NewFloat64(math.MinInt64), v)
+		assert.EqualValues(t, `"2562047h47m16.854775807s"`, d.String())
+	}
+
+	tm := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	assert.EqualValues(t, `"2020-01-02T03:04:05.000000006Z"`, FromValue(slog.TimeValue(tm)).String())
+
+	b := []byte{1, 2, 3}
+	assert.EqualValues(t, variant.NewBytes(b), FromValue(slog.AnyValue(b)))
+	assert.EqualValues(t, "[1,\"a\"]", FromValue(slog.AnyValue([]any{1, "a"})).String())
+	assert.EqualValues(t, `"{1 2}"`, FromValue(slog.AnyValue(struct{ a, b int }{1, 2})).String())
+
+	v = FromValue(slog.GroupValue(slog.Int("a", 1), slog.Group("b", slog.String("c", "x"))))
+	assert.EqualValues(t, `{"a":1,"b":{"c":"x"}}`, v.String())
+}
+
+func TestRoundTrip(t *testing.T) {
+	v := variant.NewKeyValueList(
+		[]variant.KeyValue{
+			{Key: "int", Value: variant.NewInt(1)},
+			{Key: "float", Value: variant.NewFloat64(1.5)},
+			{Key: "str", Value: variant.NewString("abc")},
+			{Key: "bytes", Value: variant.NewBytes([]byte{0xA})},
+			{Key: "floats", Value: variant.NewFloat64Array([]float64{0.5})},
+			{Key: "list", Value: variant.NewValueList([]variant.Variant{variant.NewInt(2)})},
+			{Key: "map", Value: variant.NewKeyValueList([]variant.KeyValue{{Key: "k", Value: variant.NewString("v")}})},
+		},
+	)
+
+	// A Variant passed as slog.AnyValue is a LogValuer and must be resolved.
+	assert.EqualValues(t, slog.KindLogValuer, slog.AnyValue(v).Kind())
+	assert.EqualValues(t, v.String(), FromValue(slog.AnyValue(v)).String())
+
+	kv := variant.KeyValue{Key: "root", Value: v}
+	assert.EqualValues(t, kv.Value.String(), FromAttr(Attr(kv)).Value.String())
+	assert.EqualValues(t, "root", FromAttr(Attr(kv)).Key)
+}
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	v := variant.NewKeyValueList(
+		[]variant.KeyValue{
+			{Key: "a", Value: variant.NewInt(1)},
+			{Key: "b", Value: variant.NewValueList([]variant.Variant{variant.NewString("x"), variant.NewFloat64(2.5)})},
+		},
+	)
+	logger.Info("msg", "attrs", v)
+	assert.EqualValues(t, `{"level":"INFO","msg":"msg","attrs":{"a":1,"b":["x",2.5]}}`+"\n", buf.String())
+}
@@ -0,0 +1,164 @@
+package variant
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Value implements driver.Valuer interface, which allows to pass a Variant as
+// a query argument to database/sql functions.
+//
+// TypeEmpty is stored as NULL, TypeInt as int64, TypeFloat64 as float64, TypeString
+// as string and TypeBytes as []byte. TypeValueList, TypeKeyValueList and the array
+// types are stored as JSON text (see MarshalJSON), which JSONScanner decodes. Use
+// BinaryValuer to store them in compact binary encoding instead.
+func (v Variant) Value() (driver.Value, error) {
+	switch v.Type() {
+	case TypeEmpty:
+		return nil, nil
+	case TypeInt:
+		return int64(v.IntVal()), nil
+	case TypeFloat64:
+		return v.Float64Val(), nil
+	case TypeString:
+		return v.StringVal(), nil
+	case TypeBytes:
+		return v.Bytes(), nil
+	case TypeValueList, TypeKeyValueList, TypeInt64Array, TypeFloat64Array, TypeStringArray:
+		b, err := v.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	}
+	panic(&InvalidTypeError{Type: v.Type()})
+}
+
+// BinaryValuer returns a driver.Valuer that stores TypeValueList, TypeKeyValueList and
+// the array types as a []byte blob in compact binary encoding (see MarshalBinary),
+// which BinaryScanner decodes. Other types are stored the same way as Value does.
+func (v Variant) BinaryValuer() driver.Valuer {
+	return binaryValuer(v)
+}
+
+type binaryValuer Variant
+
+func (b binaryValuer) Value() (driver.Value, error) {
+	v := Variant(b)
+	switch v.Type() {
+	case TypeValueList, TypeKeyValueList, TypeInt64Array, TypeFloat64Array, TypeStringArray:
+		return v.MarshalBinary()
+	}
+	return v.Value()
+}
+
+// Scan implements sql.Scanner interface, which allows to pass a *Variant as
+// a destination to sql.Rows.Scan and sql.Row.Scan.
+//
+// NULL is scanned as TypeEmpty, int64 as TypeInt, float64 as TypeFloat64, text as
+// TypeString, binary values as TypeBytes, bool as TypeString with value "true" or
+// "false" and time.Time as TypeString formatted as RFC 3339 with nanoseconds. An int64
+// that does not fit into int on 32 bit platforms is scanned as TypeFloat64.
+//
+// Scan does not guess the type from the content, so the values of TypeEmpty, TypeInt,
+// TypeFloat64, TypeString and TypeBytes stored by Value are scanned back as the same
+// Variant. The lists stored by Value are scanned as the TypeString that contains their
+// JSON text, use JSONScanner to decode them. Use BinaryScanner for the lists stored by
+// BinaryValuer. Note that some drivers return text columns as []byte, which will be
+// scanned as TypeBytes.
+//
+// The scanned Variant does not reference the memory owned by the driver.
+func (v *Variant) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*v = NewEmpty()
+	case int64:
+		*v = newIntFromInt64(src)
+	case float64:
+		*v = NewFloat64(src)
+	case bool:
+		*v = NewString(strconv.FormatBool(src))
+	case time.Time:
+		*v = NewString(src.Format(time.RFC3339Nano))
+	case string:
+		*v = NewString(src)
+	case []byte:
+		*v = NewBytes(append([]byte(nil), src...))
+	default:
+		return fmt.Errorf("cannot scan %T into Variant", src)
+	}
+	return nil
+}
+
+// JSONScanner returns a sql.Scanner that decodes text and binary values as JSON (see
+// UnmarshalJSON) and stores the result in v, e.g. the lists stored by Value. Returns an
+// error if the value is not valid JSON. Other values are scanned the same way as Scan
+// does.
+func (v *Variant) JSONScanner() sql.Scanner {
+	return (*jsonScanner)(v)
+}
+
+type jsonScanner Variant
+
+func (s *jsonScanner) Scan(src interface{}) error {
+	v := (*Variant)(s)
+	switch src := src.(type) {
+	case string:
+		return v.UnmarshalJSON([]byte(src))
+	case []byte:
+		return v.UnmarshalJSON(src)
+	}
+	return v.Scan(src)
+}
+
+// BinaryScanner returns a sql.Scanner that decodes binary values that are encoded by
+// MarshalBinary and stores the result in v, e.g. the lists stored by BinaryValuer.
+// Returns an error if a binary value is not encoded by MarshalBinary. Other values are
+// scanned the same way as Scan does.
+func (v *Variant) BinaryScanner() sql.Scanner {
+	return (*binaryScanner)(v)
+}
+
+type binaryScanner Variant
+
+func (s *binaryScanner) Scan(src interface{}) error {
+	v := (*Variant)(s)
+	if b, ok := src.([]byte); ok {
+		return v.UnmarshalBinary(b)
+	}
+	return v.Scan(src)
+}
+
+// ScanRows reads all remaining rows and returns them as a TypeValueList. Each
+// element of the list is a TypeKeyValueList that contains one KeyValue per column,
+// where Key is the column name and Value is the column value scanned using Scan.
+//
+// ScanRows does not close the rows, however the rows are closed automatically by
+// database/sql if all rows are read successfully.
+func ScanRows(rows *sql.Rows) (Variant, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return Variant{}, err
+	}
+
+	var list []Variant
+	dest := make([]interface{}, len(columns))
+	for rows.Next() {
+		row := make([]KeyValue, len(columns))
+		for i := range row {
+			row[i].Key = columns[i]
+			dest[i] = &row[i].Value
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return Variant{}, err
+		}
+		list = append(list, NewKeyValueList(row))
+	}
+	if err := rows.Err(); err != nil {
+		return Variant{}, err
+	}
+	return NewValueList(list), nil
+}
@@ -0,0 +1,248 @@
+package variant
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLValue(t *testing.T) {
+	tests := []struct {
+		v   Variant
+		val driver.Value
+	}{
+		{NewEmpty(), nil},
+		{NewInt(12), int64(12)},
+		{NewFloat64(1.5), 1.5},
+		{NewString("abc"), "abc"},
+		{NewBytes([]byte{1, 2}), []byte{1, 2}},
+		{NewValueList([]Variant{NewInt(1), NewString("a")}), `[1,"a"]`},
+		{NewKeyValueList([]KeyValue{{Key: "a", Value: NewFloat64(1)}}), `{"a":1.0}`},
+		{NewStringArray([]string{"a", "b"}), `["a","b"]`},
+	}
+	for _, test := range tests {
+		val, err := test.v.Value()
+		require.NoError(t, err)
+		assert.EqualValues(t, test.val, val)
+		assert.True(t, driver.IsValue(val))
+	}
+
+	val, err := NewInt(1).BinaryValuer().Value()
+	require.NoError(t, err)
+	assert.EqualValues(t, int64(1), val)
+
+	val, err = NewValueList([]Variant{NewInt(1)}).BinaryValuer().Value()
+	require.NoError(t, err)
+	assert.EqualValues(t, []byte(binaryMagic+"\x05\x01\x01\x02"), val)
+
+	val, err = NewInt64Array([]int64{1}).BinaryValuer().Value()
+	require.NoError(t, err)
+	assert.EqualValues(t, []byte(binaryMagic+"\x07\x01\x02"), val)
+}
+
+func TestSQLScan(t *testing.T) {
+	tm := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		src interface{}
+		str string
+		typ Type
+	}{
+		{nil, ``, TypeEmpty},
+		{int64(-3), `-3`, TypeInt},
+		{2.5, `2.5`, TypeFloat64},
+		{true, `"true"`, TypeString},
+		{tm, `"2020-01-02T03:04:05Z"`, TypeString},
+		{"abc", `"abc"`, TypeString},
+		// The type is not guessed from the content.
+		{` {"a":[1]}`, `" {\"a\":[1]}"`, TypeString},
+		{[]byte{1, 2}, `0x0102`, TypeBytes},
+		{[]byte(`[]`), `0x5B5D`, TypeBytes},
+		{[]byte(binaryMagic + "\x00"), `0xFF47560100`, TypeBytes},
+	}
+	for _, test := range tests {
+		var v Variant
+		require.NoError(t, v.Scan(test.src))
+		assert.EqualValues(t, test.typ, v.Type())
+		assert.EqualValues(t, test.str, v.String())
+	}
+
+	var v Variant
+	assert.Error(t, v.Scan(struct{}{}))
+
+	// Scanned bytes must not alias driver memory.
+	src := []byte{1, 2}
+	require.NoError(t, v.Scan(src))
+	src[0] = 0
+	assert.EqualValues(t, []byte{1, 2}, v.Bytes())
+}
+
+func TestScanInt64OutOfRange(t *testing.T) {
+	if strconv.IntSize != 32 {
+		t.Skip("int64 fits into int")
+	}
+	var v Variant
+	require.NoError(t, v.Scan(int64(1)<<40))
+	assert.EqualValues(t, NewFloat64(1<<40), v)
+	require.NoError(t, v.Scan(int64(-1)<<31))
+	assert.EqualValues(t, NewInt(-1<<31), v)
+}
+
+func TestSQLRoundTrip(t *testing.T) {
+	vals := []Variant{
+		NewEmpty(),
+		NewInt(-1),
+		NewFloat64(1.5),
+		NewString("abc"),
+		NewString(`[1,"a"]`),
+		NewBytes([]byte(`{"a":1}`)),
+		NewBytes([]byte(binaryMagic + "\x00")),
+	}
+	lists := []Variant{
+		NewValueList([]Variant{NewInt(1), NewString("a")}),
+		NewKeyValueList([]KeyValue{{Key: "a", Value: NewValueList(nil)}}),
+	}
+
+	// Value and Scan preserve the values that are not lists.
+	for _, v := range vals {
+		val, err := v.Value()
+		require.NoError(t, err)
+		var r Variant
+		require.NoError(t, r.Scan(val))
+		assert.EqualValues(t, v.Type(), r.Type())
+		assert.EqualValues(t, v.String(), r.String())
+	}
+
+	for _, v := range append(vals[:3:3], lists...) {
+		val, err := v.Value()
+		require.NoError(t, err)
+		var r Variant
+		require.NoError(t, r.JSONScanner().Scan(val))
+		assert.EqualValues(t, v.String(), r.String())
+
+		val, err = v.BinaryValuer().Value()
+		require.NoError(t, err)
+		r = Variant{}
+		require.NoError(t, r.BinaryScanner().Scan(val))
+		assert.EqualValues(t, v.String(), r.String())
+	}
+
+	var r Variant
+	require.NoError(t, r.JSONScanner().Scan([]byte(`[1,2]`)))
+	assert.EqualValues(t, `[1,2]`, r.String())
+	assert.Error(t, r.JSONScanner().Scan("abc"))
+	assert.Error(t, r.JSONScanner().Scan(struct{}{}))
+	assert.Error(t, r.BinaryScanner().Scan([]byte{1, 2}))
+	assert.Error(t, r.BinaryScanner().Scan([]byte(binaryMagic+"\x05")))
+	require.NoError(t, r.BinaryScanner().Scan("abc"))
+	assert.EqualValues(t, `"abc"`, r.String())
+}
+
+func TestScanRows(t *testing.T) {
+	db := sql.OpenDB(fakeConnector{
+		columns: []string{"id", "name", "attrs"},
+		rows: [][]driver.Value{
+			{int64(1), "a", []byte(`{"k":"v"}`)},
+			{int64(2), nil, []byte(binaryMagic + "\x05\x01\x01\x02")},
+		},
+	})
+	defer db.Close()
+
+	rows, err := db.Query("SELECT")
+	require.NoError(t, err)
+	v, err := ScanRows(rows)
+	require.NoError(t, err)
+	assert.EqualValues(
+		t, `[{"id":1,"name":"a","attrs":0x7B226B223A2276227D},{"id":2,"name":,"attrs":0xFF47560105010102}]`,
+		v.String(),
+	)
+
+	rows, err = db.Query("ERROR")
+	require.NoError(t, err)
+	_, err = ScanRows(rows)
+	assert.Error(t, err)
+}
+
+// fakeConnector implements a minimal database/sql driver that returns fixed rows.
+type fakeConnector struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (c fakeConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return fakeConn{c}, nil
+}
+
+func (c fakeConnector) Driver() driver.Driver {
+	return nil
+}
+
+type fakeConn struct {
+	c fakeConnector
+}
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return fakeStmt{c: c.c, fail: query == "ERROR"}, nil
+}
+
+func (c fakeConn) Close() error {
+	return nil
+}
+
+func (c fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+type fakeStmt struct {
+	c    fakeConnector
+	fail bool
+}
+
+func (s fakeStmt) Close() error {
+	return nil
+}
+
+func (s fakeStmt) NumInput() int {
+	return 0
+}
+
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &fakeRows{c: s.c, fail: s.fail}, nil
+}
+
+type fakeRows struct {
+	c    fakeConnector
+	fail bool
+	i    int
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.c.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.fail {
+		return errors.New("query failed")
+	}
+	if r.i >= len(r.c.rows) {
+		return io.EOF
+	}
+	copy(dest, r.c.rows[r.i])
+	r.i++
+	return nil
+}
@@ -0,0 +1,214 @@
+package variant
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// TokenKind is the kind of a Token.
+type TokenKind int
+
+const (
+	// TokenValue is a value that is not a list, i.e. of TypeEmpty, TypeInt,
+	// TypeFloat64, TypeString, TypeBytes or one of the array types.
+	TokenValue TokenKind = iota
+
+	// TokenBeginList starts a TypeValueList. The following tokens are the elements
+	// of the list until the matching TokenEndList.
+	TokenBeginList
+
+	// TokenEndList ends a TypeValueList.
+	TokenEndList
+
+	// TokenBeginMap starts a TypeKeyValueList. The following tokens are the pairs of
+	// TokenKey and the value of the key until the matching TokenEndMap.
+	TokenBeginMap
+
+	// TokenEndMap ends a TypeKeyValueList.
+	TokenEndMap
+
+	// TokenKey is the key of the following value in a TypeKeyValueList.
+	TokenKey
+)
+
+var tokenKindNames = [...]string{
+	TokenValue:     "Value",
+	TokenBeginList: "BeginList",
+	TokenEndList:   "EndList",
+	TokenBeginMap:  "BeginMap",
+	TokenEndMap:    "EndMap",
+	TokenKey:       "Key",
+}
+
+// String returns the name of the kind, e.g. "BeginList" for TokenBeginList, or
+// "TokenKind(n)" if k is not one of the TokenKind constants.
+func (k TokenKind) String() string {
+	if uint(k) < uint(len(tokenKindNames)) {
+		return tokenKindNames[k]
+	}
+	return "TokenKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Token is an element of a stream that describes Variants in the order they are
+// written, e.g. the value {"a":[1]} is the sequence of tokens BeginMap, Key "a",
+// BeginList, Value 1, EndList, EndMap. Tokens allow to read and write values that
+// are too large to be stored in memory as a whole.
+type Token struct {
+	Kind TokenKind
+
+	// Value of a TokenValue. A TokenValue that contains a list is valid too and
+	// is equivalent to the tokens of the list.
+	Value Variant
+
+	// Key of a TokenKey.
+	Key string
+}
+
+// TokenReader is the interface of the decoders that read a stream of tokens, see
+// JSONTokenReader.
+type TokenReader interface {
+	// Token returns the next token. Returns io.EOF at the end of the stream, which
+	// can contain any number of top-level values.
+	Token() (Token, error)
+}
+
+// TokenWriter is the interface of the encoders that write a stream of tokens, see
+// JSONTokenWriter.
+type TokenWriter interface {
+	// WriteToken writes the token. Returns an error if the token cannot follow the
+	// previous tokens, e.g. if TokenEndList does not match a TokenBeginList.
+	WriteToken(t Token) error
+}
+
+// ReadVariant reads the tokens of one top-level value from r and returns the value.
+// Returns io.EOF if r returns io.EOF before the first token of the value and
+// io.ErrUnexpectedEOF if it returns io.EOF before the value is complete. Returns an
+// error if the tokens do not form a valid value.
+//
+// The value is built by a Builder, so the lists of the value are exactly sized.
+func ReadVariant(r TokenReader) (Variant, error) {
+	var b Builder
+	var c tokenChecker
+	for first := true; ; first = false {
+		t, err := r.Token()
+		if err != nil {
+			if err == io.EOF && !first {
+				err = io.ErrUnexpectedEOF
+			}
+			return Variant{}, err
+		}
+		if err := c.check(t); err != nil {
+			return Variant{}, err
+		}
+		switch t.Kind {
+		case TokenKey:
+			b.Key(t.Key)
+			continue
+		case TokenBeginList:
+			b.BeginList()
+		case TokenBeginMap:
+			b.BeginMap()
+		case TokenEndList, TokenEndMap:
+			b.End()
+		default:
+			b.Value(t.Value)
+		}
+		if len(c.maps) == 0 {
+			return b.Result(), nil
+		}
+	}
+}
+
+// WriteVariant writes the tokens of v to w. The values that are not lists are written
+// as one TokenValue each. Like Walk, WriteVariant requires that the lists do not
+// contain themselves.
+func WriteVariant(w TokenWriter, v Variant) error {
+	pre := func(path Path, v *Variant) error {
+		if len(path) > 0 && path[len(path)-1].HasKey {
+			if err := w.WriteToken(Token{Kind: TokenKey, Key: path[len(path)-1].Key}); err != nil {
+				return err
+			}
+		}
+		switch v.Type() {
+		case TypeValueList:
+			return w.WriteToken(Token{Kind: TokenBeginList})
+		case TypeKeyValueList:
+			return w.WriteToken(Token{Kind: TokenBeginMap})
+		}
+		return w.WriteToken(Token{Kind: TokenValue, Value: *v})
+	}
+	post := func(path Path, v *Variant) error {
+		switch v.Type() {
+		case TypeValueList:
+			return w.WriteToken(Token{Kind: TokenEndList})
+		case TypeKeyValueList:
+			return w.WriteToken(Token{Kind: TokenEndMap})
+		}
+		return nil
+	}
+	return Walk(&v, pre, post)
+}
+
+// tokenChecker checks that a sequence of tokens forms valid values.
+type tokenChecker struct {
+	// maps are the open lists, true for TypeKeyValueList.
+	maps []bool
+
+	// key is the key of the next value in a map, if hasKey is true.
+	key    string
+	hasKey bool
+}
+
+// inMap returns true if the innermost open list is a TypeKeyValueList.
+func (c *tokenChecker) inMap() bool {
+	return len(c.maps) > 0 && c.maps[len(c.maps)-1]
+}
+
+// check returns an error if t cannot follow the previous tokens, otherwise it adds t
+// to the state of the checker.
+func (c *tokenChecker) check(t Token) error {
+	inMap := c.inMap()
+	switch t.Kind {
+	case TokenKey:
+		if !inMap {
+			return tokenError(t.Kind, "not in a map")
+		}
+		if c.hasKey {
+			return tokenError(t.Kind, "the previous key has no value")
+		}
+		c.key, c.hasKey = t.Key, true
+		return nil
+
+	case TokenEndList, TokenEndMap:
+		if len(c.maps) == 0 {
+			return tokenError(t.Kind, "no list is open")
+		}
+		if inMap && t.Kind == TokenEndList {
+			return tokenError(t.Kind, "the innermost list is a map")
+		}
+		if !inMap && t.Kind == TokenEndMap {
+			return tokenError(t.Kind, "the innermost list is not a map")
+		}
+		if c.hasKey {
+			return tokenError(t.Kind, "key "+strconv.Quote(c.key)+" has no value")
+		}
+		c.maps = c.maps[:len(c.maps)-1]
+		return nil
+
+	case TokenValue, TokenBeginList, TokenBeginMap:
+		if inMap && !c.hasKey {
+			return tokenError(t.Kind, "a value in a map must be preceded by a key")
+		}
+		c.key, c.hasKey = "", false
+		if t.Kind != TokenValue {
+			c.maps = append(c.maps, t.Kind == TokenBeginMap)
+		}
+		return nil
+	}
+	return fmt.Errorf("invalid token kind %v", t.Kind)
+}
+
+func tokenError(kind TokenKind, reason string) error {
+	return fmt.Errorf("unexpected %v token: %s", kind, reason)
+}
@@ -0,0 +1,181 @@
+package variant
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tokenList is a TokenReader and TokenWriter that stores the tokens in a slice.
+type tokenList struct {
+	tokens  []Token
+	checker tokenChecker
+}
+
+func (l *tokenList) Token() (Token, error) {
+	if len(l.tokens) == 0 {
+		return Token{}, io.EOF
+	}
+	t := l.tokens[0]
+	l.tokens = l.tokens[1:]
+	return t, nil
+}
+
+func (l *tokenList) WriteToken(t Token) error {
+	if err := l.checker.check(t); err != nil {
+		return err
+	}
+	l.tokens = append(l.tokens, t)
+	return nil
+}
+
+func valueToken(v Variant) Token {
+	return Token{Kind: TokenValue, Value: v}
+}
+
+func keyToken(k string) Token {
+	return Token{Kind: TokenKey, Key: k}
+}
+
+var (
+	beginListToken = Token{Kind: TokenBeginList}
+	endListToken   = Token{Kind: TokenEndList}
+	beginMapToken  = Token{Kind: TokenBeginMap}
+	endMapToken    = Token{Kind: TokenEndMap}
+)
+
+// tokenStrings formats the tokens for comparison, since Variants that refer to
+// different memory are not equal for assert.EqualValues.
+func tokenStrings(tokens []Token) []string {
+	var r []string
+	for _, t := range tokens {
+		switch t.Kind {
+		case TokenValue:
+			r = append(r, t.Value.GoString())
+		case TokenKey:
+			r = append(r, "Key "+t.Key)
+		default:
+			r = append(r, t.Kind.String())
+		}
+	}
+	return r
+}
+
+func TestTokenKindString(t *testing.T) {
+	assert.EqualValues(t, "Value", TokenValue.String())
+	assert.EqualValues(t, "EndMap", TokenEndMap.String())
+	assert.EqualValues(t, "Key", TokenKey.String())
+	assert.EqualValues(t, "TokenKind(6)", TokenKind(6).String())
+	assert.EqualValues(t, "TokenKind(-1)", TokenKind(-1).String())
+}
+
+func TestWriteVariant(t *testing.T) {
+	v := NewKeyValueList([]KeyValue{
+		{Key: "a", Value: NewValueList([]Variant{NewInt(1), NewKeyValueList([]KeyValue{})})},
+		{Key: "b", Value: NewInt64Array([]int64{2})},
+		{Key: "c", Value: NewValueList([]Variant{})},
+	})
+	var l tokenList
+	require.NoError(t, WriteVariant(&l, v))
+	assert.EqualValues(
+		t, tokenStrings([]Token{
+			beginMapToken,
+			keyToken("a"), beginListToken, valueToken(NewInt(1)), beginMapToken, endMapToken, endListToken,
+			keyToken("b"), valueToken(NewInt64Array([]int64{2})),
+			keyToken("c"), beginListToken, endListToken,
+			endMapToken,
+		}), tokenStrings(l.tokens),
+	)
+
+	// The tokens are read back as the same value.
+	r, err := ReadVariant(&l)
+	require.NoError(t, err)
+	assert.EqualValues(t, v.GoString(), r.GoString())
+	_, err = ReadVariant(&l)
+	assert.Equal(t, io.EOF, err)
+
+	l = tokenList{}
+	require.NoError(t, WriteVariant(&l, NewString("a")))
+	assert.EqualValues(t, []Token{valueToken(NewString("a"))}, l.tokens)
+}
+
+// errTokenWriter fails after n tokens.
+type errTokenWriter struct {
+	n int
+}
+
+var errTokenWriterFailed = errors.New("write failed")
+
+func (w *errTokenWriter) WriteToken(t Token) error {
+	if w.n == 0 {
+		return errTokenWriterFailed
+	}
+	w.n--
+	return nil
+}
+
+func TestWriteVariantError(t *testing.T) {
+	v := NewKeyValueList([]KeyValue{{Key: "a", Value: NewValueList([]Variant{NewInt(1)})}})
+	// The tokens are BeginMap, Key, BeginList, Value, EndList and EndMap.
+	for n := 0; n < 6; n++ {
+		assert.Equal(t, errTokenWriterFailed, WriteVariant(&errTokenWriter{n: n}, v), n)
+	}
+	assert.NoError(t, WriteVariant(&errTokenWriter{n: 6}, v))
+}
+
+func TestReadVariant(t *testing.T) {
+	l := tokenList{tokens: []Token{
+		valueToken(NewInt(1)),
+		beginListToken,
+		valueToken(NewValueList([]Variant{NewString("a")})),
+		beginMapToken, keyToken("k"), valueToken(NewInt(2)), endMapToken,
+		endListToken,
+	}}
+	v, err := ReadVariant(&l)
+	require.NoError(t, err)
+	assert.EqualValues(t, "1", v.String())
+	v, err = ReadVariant(&l)
+	require.NoError(t, err)
+	assert.EqualValues(t, `[["a"],{"k":2}]`, v.String())
+	_, err = ReadVariant(&l)
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestReadVariantErrors(t *testing.T) {
+	tests := []struct {
+		tokens []Token
+		err    string
+	}{
+		{[]Token{beginListToken}, io.ErrUnexpectedEOF.Error()},
+		{[]Token{beginMapToken, keyToken("a")}, io.ErrUnexpectedEOF.Error()},
+		{[]Token{endListToken}, "unexpected EndList token: no list is open"},
+		{[]Token{keyToken("a")}, "unexpected Key token: not in a map"},
+		{[]Token{beginListToken, keyToken("a")}, "unexpected Key token: not in a map"},
+		{
+			[]Token{beginMapToken, keyToken("a"), keyToken("b")},
+			"unexpected Key token: the previous key has no value",
+		},
+		{
+			[]Token{beginMapToken, valueToken(NewInt(1))},
+			"unexpected Value token: a value in a map must be preceded by a key",
+		},
+		{
+			[]Token{beginMapToken, beginListToken},
+			"unexpected BeginList token: a value in a map must be preceded by a key",
+		},
+		{[]Token{beginMapToken, endListToken}, "unexpected EndList token: the innermost list is a map"},
+		{[]Token{beginListToken, endMapToken}, "unexpected EndMap token: the innermost list is not a map"},
+		{
+			[]Token{beginMapToken, keyToken("a"), endMapToken},
+			`unexpected EndMap token: key "a" has no value`,
+		},
+		{[]Token{{Kind: 10}}, "invalid token kind TokenKind(10)"},
+	}
+	for _, test := range tests {
+		_, err := ReadVariant(&tokenList{tokens: test.tokens})
+		assert.EqualError(t, err, test.err, test.tokens)
+	}
+}
@@ -0,0 +1,710 @@
+package toml
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tigrannajaryan/govariant/variant"
+)
+
+// table is a TOML table that is being built by the parser.
+type table struct {
+	// Keys in the order of definition.
+	keys    []string
+	entries map[string]*entry
+
+	// True if the table was defined by a [header] (or is the root table).
+	defined bool
+
+	// True if the table was created by dotted keys.
+	dotted bool
+}
+
+type entryKind int
+
+const (
+	// A value, including arrays and inline tables, which cannot be extended.
+	entryValue entryKind = iota
+
+	// A table.
+	entryTable
+
+	// An array of tables.
+	entryArray
+)
+
+type entry struct {
+	kind   entryKind
+	value  variant.Variant
+	table  *table
+	tables []*table
+}
+
+func newTable() *table {
+	return &table{entries: map[string]*entry{}}
+}
+
+func (t *table) add(key string, e *entry) {
+	t.keys = append(t.keys, key)
+	t.entries[key] = e
+}
+
+// variant converts the table to a TypeKeyValueList.
+func (t *table) variant() variant.Variant {
+	list := make([]variant.KeyValue, len(t.keys))
+	for i, k := range t.keys {
+		list[i].Key = k
+		e := t.entries[k]
+		switch e.kind {
+		case entryValue:
+			list[i].Value = e.value
+		case entryTable:
+			list[i].Value = e.table.variant()
+		case entryArray:
+			arr := make([]variant.Variant, len(e.tables))
+			for j, t := range e.tables {
+				arr[j] = t.variant()
+			}
+			list[i].Value = variant.NewValueList(arr)
+		}
+	}
+	return variant.NewKeyValueList(list)
+}
+
+type parser struct {
+	data []byte
+	pos  int
+	line int
+
+	root *table
+
+	// The table to which the key/value pairs are currently added.
+	cur *table
+}
+
+func newParser(data []byte) *parser {
+	root := newTable()
+	root.defined = true
+	return &parser{data: data, line: 1, root: root, cur: root}
+}
+
+// parseError is returned for syntax and semantic errors in the document.
+type parseError struct {
+	line int
+	msg  string
+}
+
+func (e *parseError) Error() string {
+	return fmt.Sprintf("toml: line %d: %s", e.line, e.msg)
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &parseError{line: p.line, msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.data)
+}
+
+func (p *parser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.data[p.pos]
+}
+
+func (p *parser) hasPrefix(s string) bool {
+	return len(p.data)-p.pos >= len(s) && string(p.data[p.pos:p.pos+len(s)]) == s
+}
+
+func (p *parser) parse() error {
+	if !utf8.Valid(p.data) {
+		return errors.New("toml: document is not valid UTF-8")
+	}
+	for {
+		p.skipSpaceAndNewlines()
+		if p.eof() {
+			return nil
+		}
+
+		var err error
+		switch {
+		case p.hasPrefix("[["):
+			err = p.arrayHeader()
+		case p.peek() == '[':
+			err = p.tableHeader()
+		default:
+			err = p.keyValue(p.cur)
+		}
+		if err != nil {
+			return err
+		}
+		if err := p.endOfLine(); err != nil {
+			return err
+		}
+	}
+}
+
+// skipSpace skips spaces and tabs.
+func (p *parser) skipSpace() {
+	for !p.eof() && (p.data[p.pos] == ' ' || p.data[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// skipComment skips a comment if there is one at the current position.
+func (p *parser) skipComment() {
+	if p.peek() != '#' {
+		return
+	}
+	for !p.eof() && p.data[p.pos] != '\n' && !p.hasPrefix("\r\n") {
+		p.pos++
+	}
+}
+
+// newline consumes a newline if there is one at the current position.
+func (p *parser) newline() bool {
+	switch {
+	case p.peek() == '\n':
+		p.pos++
+	case p.hasPrefix("\r\n"):
+		p.pos += 2
+	default:
+		return false
+	}
+	p.line++
+	return true
+}
+
+// skipSpaceAndNewlines skips spaces, comments and newlines.
+func (p *parser) skipSpaceAndNewlines() {
+	for {
+		p.skipSpace()
+		p.skipComment()
+		if !p.newline() {
+			return
+		}
+	}
+}
+
+// endOfLine consumes optional spaces and a comment, followed by a newline or EOF.
+func (p *parser) endOfLine() error {
+	p.skipSpace()
+	p.skipComment()
+	if !p.eof() && !p.newline() {
+		return p.errorf("expected newline, found %q", p.peek())
+	}
+	return nil
+}
+
+func (p *parser) expect(c byte) error {
+	if p.peek() != c {
+		if p.eof() {
+			return p.errorf("expected %q, found end of document", c)
+		}
+		return p.errorf("expected %q, found %q", c, p.peek())
+	}
+	p.pos++
+	return nil
+}
+
+// tableHeader parses [a.b.c] header and makes the table current.
+func (p *parser) tableHeader() error {
+	p.pos++
+	p.skipSpace()
+	path, err := p.key()
+	if err != nil {
+		return err
+	}
+	if err := p.expect(']'); err != nil {
+		return err
+	}
+
+	parent, err := p.walk(path[:len(path)-1])
+	if err != nil {
+		return err
+	}
+
+	key := path[len(path)-1]
+	e := parent.entries[key]
+	switch {
+	case e == nil:
+		t := newTable()
+		t.defined = true
+		parent.add(key, &entry{kind: entryTable, table: t})
+		p.cur = t
+	case e.kind == entryTable && !e.table.defined && !e.table.dotted:
+		// The table was created implicitly by a previous header.
+		e.table.defined = true
+		p.cur = e.table
+	default:
+		return p.errorf("key %q is already defined", strings.Join(path, "."))
+	}
+	return nil
+}
+
+// arrayHeader parses [[a.b.c]] header, appends a new table to the array of tables
+// and makes it current.
+func (p *parser) arrayHeader() error {
+	p.pos += 2
+	p.skipSpace()
+	path, err := p.key()
+	if err != nil {
+		return err
+	}
+	if !p.hasPrefix("]]") {
+		return p.errorf("expected \"]]\"")
+	}
+	p.pos += 2
+
+	parent, err := p.walk(path[:len(path)-1])
+	if err != nil {
+		return err
+	}
+
+	key := path[len(path)-1]
+	t := newTable()
+	t.defined = true
+	e := parent.entries[key]
+	switch {
+	case e == nil:
+		parent.add(key, &entry{kind: entryArray, tables: []*table{t}})
+	case e.kind == entryArray:
+		e.tables = append(e.tables, t)
+	default:
+		return p.errorf("key %q is already defined", strings.Join(path, "."))
+	}
+	p.cur = t
+	return nil
+}
+
+// walk finds the table that the header path refers to, starting from the root
+// table and creating missing tables implicitly.
+func (p *parser) walk(path []string) (*table, error) {
+	t := p.root
+	for i, key := range path {
+		e := t.entries[key]
+		switch {
+		case e == nil:
+			n := newTable()
+			t.add(key, &entry{kind: entryTable, table: n})
+			t = n
+		case e.kind == entryTable:
+			t = e.table
+		case e.kind == entryArray:
+			t = e.tables[len(e.tables)-1]
+		default:
+			return nil, p.errorf("key %q is already defined as a value", strings.Join(path[:i+1], "."))
+		}
+	}
+	return t, nil
+}
+
+// keyValue parses key = value pair and adds it to table t.
+func (p *parser) keyValue(t *table) error {
+	path, err := p.key()
+	if err != nil {
+		return err
+	}
+	if err := p.expect('='); err != nil {
+		return err
+	}
+	p.skipSpace()
+	v, err := p.value()
+	if err != nil {
+		return err
+	}
+
+	for i, key := range path[:len(path)-1] {
+		e := t.entries[key]
+		switch {
+		case e == nil:
+			n := newTable()
+			n.dotted = true
+			t.add(key, &entry{kind: entryTable, table: n})
+			t = n
+		case e.kind == entryTable && e.table.dotted:
+			t = e.table
+		default:
+			return p.errorf("key %q is already defined", strings.Join(path[:i+1], "."))
+		}
+	}
+
+	key := path[len(path)-1]
+	if t.entries[key] != nil {
+		return p.errorf("key %q is already defined", strings.Join(path, "."))
+	}
+	t.add(key, &entry{kind: entryValue, value: v})
+	return nil
+}
+
+// key parses a possibly dotted key and the spaces that follow it.
+func (p *parser) key() ([]string, error) {
+	var path []string
+	for {
+		k, err := p.simpleKey()
+		if err != nil {
+			return nil, err
+		}
+		path = append(path, k)
+		p.skipSpace()
+		if p.peek() != '.' {
+			return path, nil
+		}
+		p.pos++
+		p.skipSpace()
+	}
+}
+
+func isBareKeyChar(c byte) bool {
+	return c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+func (p *parser) simpleKey() (string, error) {
+	switch p.peek() {
+	case '"':
+		if p.hasPrefix(`"""`) {
+			return "", p.errorf("multi-line string cannot be used as a key")
+		}
+		return p.basicString()
+	case '\'':
+		if p.hasPrefix(`'''`) {
+			return "", p.errorf("multi-line string cannot be used as a key")
+		}
+		return p.literalString()
+	}
+	start := p.pos
+	for !p.eof() && isBareKeyChar(p.data[p.pos]) {
+		p.pos++
+	}
+	if start == p.pos {
+		if p.eof() {
+			return "", p.errorf("expected a key, found end of document")
+		}
+		return "", p.errorf("expected a key, found %q", p.peek())
+	}
+	return string(p.data[start:p.pos]), nil
+}
+
+func (p *parser) value() (variant.Variant, error) {
+	switch {
+	case p.hasPrefix(`"""`):
+		s, err := p.multiLineString('"')
+		return variant.NewString(s), err
+	case p.hasPrefix(`'''`):
+		s, err := p.multiLineString('\'')
+		return variant.NewString(s), err
+	case p.peek() == '"':
+		s, err := p.basicString()
+		return variant.NewString(s), err
+	case p.peek() == '\'':
+		s, err := p.literalString()
+		return variant.NewString(s), err
+	case p.peek() == '[':
+		return p.array()
+	case p.peek() == '{':
+		return p.inlineTable()
+	}
+	return p.scalar()
+}
+
+func (p *parser) array() (variant.Variant, error) {
+	p.pos++
+	var list []variant.Variant
+	for {
+		p.skipSpaceAndNewlines()
+		if p.peek() == ']' {
+			p.pos++
+			return variant.NewValueList(list), nil
+		}
+		v, err := p.value()
+		if err != nil {
+			return variant.Variant{}, err
+		}
+		list = append(list, v)
+
+		p.skipSpaceAndNewlines()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return variant.NewValueList(list), nil
+		default:
+			return variant.Variant{}, p.errorf("expected ',' or ']' in array")
+		}
+	}
+}
+
+func (p *parser) inlineTable() (variant.Variant, error) {
+	p.pos++
+	t := newTable()
+	p.skipSpace()
+	if p.peek() == '}' {
+		p.pos++
+		return t.variant(), nil
+	}
+	for {
+		p.skipSpace()
+		if err := p.keyValue(t); err != nil {
+			return variant.Variant{}, err
+		}
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return t.variant(), nil
+		default:
+			return variant.Variant{}, p.errorf("expected ',' or '}' in inline table")
+		}
+	}
+}
+
+func (p *parser) basicString() (string, error) {
+	p.pos++
+	var sb strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated string")
+		}
+		c := p.data[p.pos]
+		switch {
+		case c == '"':
+			p.pos++
+			return sb.String(), nil
+		case c == '\\':
+			if err := p.escape(&sb, false); err != nil {
+				return "", err
+			}
+		case isControl(c):
+			return "", p.errorf("control character %q is not allowed in string", c)
+		default:
+			sb.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+func (p *parser) literalString() (string, error) {
+	p.pos++
+	start := p.pos
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated string")
+		}
+		c := p.data[p.pos]
+		if c == '\'' {
+			s := string(p.data[start:p.pos])
+			p.pos++
+			return s, nil
+		}
+		if isControl(c) {
+			return "", p.errorf("control character %q is not allowed in string", c)
+		}
+		p.pos++
+	}
+}
+
+// multiLineString parses a multi-line basic string if quote is a double quote or
+// a multi-line literal string if quote is a single quote.
+func (p *parser) multiLineString(quote byte) (string, error) {
+	p.pos += 3
+	// A newline immediately following the opening delimiter is trimmed.
+	p.newline()
+
+	var sb strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated multi-line string")
+		}
+		c := p.data[p.pos]
+		switch {
+		case c == quote && p.hasPrefix(strings.Repeat(string(quote), 3)):
+			// Up to 2 additional quotes are allowed right before the closing delimiter.
+			n := 3
+			for n < 5 && p.pos+n < len(p.data) && p.data[p.pos+n] == quote {
+				n++
+			}
+			sb.WriteString(strings.Repeat(string(quote), n-3))
+			p.pos += n
+			return sb.String(), nil
+		case c == '\\' && quote == '"':
+			if err := p.escape(&sb, true); err != nil {
+				return "", err
+			}
+		case c == '\n' || c == '\r':
+			if !p.newline() {
+				return "", p.errorf("control character %q is not allowed in string", c)
+			}
+			sb.WriteByte('\n')
+		case isControl(c):
+			return "", p.errorf("control character %q is not allowed in string", c)
+		default:
+			sb.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+// isControl returns true for the control characters that are not allowed to appear
+// in strings unescaped.
+func isControl(c byte) bool {
+	return c < 0x20 && c != '\t' || c == 0x7F
+}
+
+// escape parses an escape sequence in a basic string. Line ending backslash is
+// only allowed in multi-line strings.
+func (p *parser) escape(sb *strings.Builder, multiLine bool) error {
+	p.pos++
+	if p.eof() {
+		return p.errorf("unterminated string")
+	}
+	c := p.data[p.pos]
+	p.pos++
+	switch c {
+	case 'b':
+		sb.WriteByte('\b')
+	case 't':
+		sb.WriteByte('\t')
+	case 'n':
+		sb.WriteByte('\n')
+	case 'f':
+		sb.WriteByte('\f')
+	case 'r':
+		sb.WriteByte('\r')
+	case '"':
+		sb.WriteByte('"')
+	case '\\':
+		sb.WriteByte('\\')
+	case 'u', 'U':
+		n := 4
+		if c == 'U' {
+			n = 8
+		}
+		if p.pos+n > len(p.data) {
+			return p.errorf("invalid unicode escape")
+		}
+		code, err := strconv.ParseUint(string(p.data[p.pos:p.pos+n]), 16, 32)
+		r := rune(code)
+		if err != nil || !utf8.ValidRune(r) {
+			return p.errorf("invalid unicode escape %q", p.data[p.pos-2:p.pos+n])
+		}
+		sb.WriteRune(r)
+		p.pos += n
+	case ' ', '\t', '\r', '\n':
+		if !multiLine {
+			return p.errorf("invalid escape sequence")
+		}
+		// Line ending backslash trims all whitespace and newlines up to the next
+		// non-whitespace character.
+		p.pos--
+		p.skipSpace()
+		if !p.newline() {
+			return p.errorf("invalid escape sequence")
+		}
+		for {
+			p.skipSpace()
+			if !p.newline() {
+				break
+			}
+		}
+	default:
+		return p.errorf("invalid escape sequence \\%c", c)
+	}
+	return nil
+}
+
+var (
+	decIntRe   = regexp.MustCompile(`^[+-]?(0|[1-9](_?[0-9])*)$`)
+	hexIntRe   = regexp.MustCompile(`^0x[0-9A-Fa-f](_?[0-9A-Fa-f])*$`)
+	octIntRe   = regexp.MustCompile(`^0o[0-7](_?[0-7])*$`)
+	binIntRe   = regexp.MustCompile(`^0b[01](_?[01])*$`)
+	floatRe    = regexp.MustCompile(`^[+-]?(0|[1-9](_?[0-9])*)(\.[0-9](_?[0-9])*)?([eE][+-]?[0-9](_?[0-9])*)?$`)
+	dateTimeRe = regexp.MustCompile(
+		`^([0-9]{4}-[0-9]{2}-[0-9]{2}([Tt ][0-9]{2}:[0-9]{2}:[0-9]{2}(\.[0-9]+)?([Zz]|[+-][0-9]{2}:[0-9]{2})?)?` +
+			`|[0-9]{2}:[0-9]{2}:[0-9]{2}(\.[0-9]+)?)$`,
+	)
+	dateRe = regexp.MustCompile(`^[0-9]{4}-[0-9]{2}-[0-9]{2}$`)
+)
+
+func isScalarChar(c byte) bool {
+	return isBareKeyChar(c) || c == '+' || c == '.' || c == ':'
+}
+
+// scalar parses a boolean, a number or a date/time value.
+func (p *parser) scalar() (variant.Variant, error) {
+	start := p.pos
+	for !p.eof() && isScalarChar(p.data[p.pos]) {
+		p.pos++
+	}
+	tok := string(p.data[start:p.pos])
+	if dateRe.MatchString(tok) && p.pos+1 < len(p.data) && p.data[p.pos] == ' ' &&
+		p.data[p.pos+1] >= '0' && p.data[p.pos+1] <= '9' {
+		// Date and time separated by a space.
+		p.pos++
+		for !p.eof() && isScalarChar(p.data[p.pos]) {
+			p.pos++
+		}
+		tok = string(p.data[start:p.pos])
+	}
+
+	switch tok {
+	case "":
+		if p.eof() {
+			return variant.Variant{}, p.errorf("expected a value, found end of document")
+		}
+		return variant.Variant{}, p.errorf("expected a value, found %q", p.peek())
+	case "true", "false":
+		return variant.NewString(tok), nil
+	case "inf", "+inf":
+		return variant.NewFloat64(math.Inf(1)), nil
+	case "-inf":
+		return variant.NewFloat64(math.Inf(-1)), nil
+	case "nan", "+nan", "-nan":
+		return variant.NewFloat64(math.NaN()), nil
+	}
+
+	if dateTimeRe.MatchString(tok) {
+		return variant.NewString(tok), nil
+	}
+
+	base := 0
+	switch {
+	case decIntRe.MatchString(tok):
+		base = 10
+	case hexIntRe.MatchString(tok):
+		base = 16
+	case octIntRe.MatchString(tok):
+		base = 8
+	case binIntRe.MatchString(tok):
+		base = 2
+	}
+	digits := strings.Replace(tok, "_", "", -1)
+	if base != 0 {
+		if base != 10 {
+			digits = digits[2:]
+		}
+		i, err := strconv.ParseInt(digits, base, 0)
+		if err != nil {
+			return variant.Variant{}, p.errorf("integer %s is out of range", tok)
+		}
+		return variant.NewInt(int(i)), nil
+	}
+
+	if floatRe.MatchString(tok) {
+		f, err := strconv.ParseFloat(digits, 64)
+		if err != nil {
+			return variant.Variant{}, p.errorf("float %s is out of range", tok)
+		}
+		return variant.NewFloat64(f), nil
+	}
+
+	return variant.Variant{}, p.errorf("invalid value %q", tok)
+}
@@ -0,0 +1,299 @@
+package toml
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tigrannajaryan/govariant/variant"
+)
+
+// encodeError is returned when a Variant cannot be expressed in TOML.
+type encodeError struct {
+	path []string
+	msg  string
+}
+
+func (e *encodeError) Error() string {
+	if len(e.path) == 0 {
+		return "toml: " + e.msg
+	}
+	return fmt.Sprintf("toml: key %s: %s", formatPath(e.path), e.msg)
+}
+
+type encoder struct {
+	buf     bytes.Buffer
+	version Version
+}
+
+func (e *encoder) document(v variant.Variant) error {
+	if v.Type() != variant.TypeKeyValueList {
+		return &encodeError{msg: fmt.Sprintf("top-level value must be a KeyValueList, found %v", v.Type())}
+	}
+	return e.table(nil, v.KeyValueList())
+}
+
+// isArrayOfTables returns true if v is encoded as an array of tables.
+func isArrayOfTables(v variant.Variant) bool {
+	if v.Type() != variant.TypeValueList || v.Len() == 0 {
+		return false
+	}
+	for _, e := range v.ValueList() {
+		if e.Type() != variant.TypeKeyValueList {
+			return false
+		}
+	}
+	return true
+}
+
+// table writes the content of the table with the specified path. The header of
+// the table must be already written. The keys that are encoded inline are written
+// before the sub-tables, so the keys of list can be reordered.
+func (e *encoder) table(path []string, list []variant.KeyValue) error {
+	if err := checkKeys(path, list); err != nil {
+		return err
+	}
+
+	// TOML requires key/value pairs of the table to precede the sub-tables.
+	for _, kv := range list {
+		if kv.Value.Type() == variant.TypeKeyValueList || isArrayOfTables(kv.Value) {
+			continue
+		}
+		if err := checkString(path, kv.Key); err != nil {
+			return err
+		}
+		e.key(kv.Key)
+		e.buf.WriteString(" = ")
+		if err := e.value(childPath(path, kv.Key), kv.Value); err != nil {
+			return err
+		}
+		e.buf.WriteByte('\n')
+	}
+
+	for _, kv := range list {
+		child := childPath(path, kv.Key)
+		switch {
+		case kv.Value.Type() == variant.TypeKeyValueList:
+			if err := checkString(path, kv.Key); err != nil {
+				return err
+			}
+			e.header("[", child, "]")
+			if err := e.table(child, kv.Value.KeyValueList()); err != nil {
+				return err
+			}
+		case isArrayOfTables(kv.Value):
+			if err := checkString(path, kv.Key); err != nil {
+				return err
+			}
+			for _, t := range kv.Value.ValueList() {
+				e.header("[[", child, "]]")
+				if err := e.table(child, t.KeyValueList()); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (e *encoder) header(open string, path []string, close string) {
+	if e.buf.Len() > 0 {
+		e.buf.WriteByte('\n')
+	}
+	e.buf.WriteString(open)
+	e.buf.WriteString(formatPath(path))
+	e.buf.WriteString(close)
+	e.buf.WriteByte('\n')
+}
+
+func formatPath(path []string) string {
+	var sb strings.Builder
+	for i, k := range path {
+		if i > 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteString(formatKey(k))
+	}
+	return sb.String()
+}
+
+func formatKey(k string) string {
+	if k == "" {
+		return `""`
+	}
+	for i := 0; i < len(k); i++ {
+		if !isBareKeyChar(k[i]) {
+			return formatString(k)
+		}
+	}
+	return k
+}
+
+func (e *encoder) key(k string) {
+	e.buf.WriteString(formatKey(k))
+}
+
+// checkString returns an error if s cannot be represented in TOML.
+func checkString(path []string, s string) error {
+	if !utf8.ValidString(s) {
+		return &encodeError{path: path, msg: fmt.Sprintf("string %q is not valid UTF-8", s)}
+	}
+	return nil
+}
+
+// checkKeys returns an error if list contains a key more than once, since TOML does
+// not allow to define a key of a table twice.
+func checkKeys(path []string, list []variant.KeyValue) error {
+	seen := make(map[string]bool, len(list))
+	for _, kv := range list {
+		if seen[kv.Key] {
+			return &encodeError{path: path, msg: fmt.Sprintf("key %q is defined more than once", kv.Key)}
+		}
+		seen[kv.Key] = true
+	}
+	return nil
+}
+
+// value writes an inline value.
+func (e *encoder) value(path []string, v variant.Variant) error {
+	switch v.Type() {
+	case variant.TypeEmpty:
+		return &encodeError{path: path, msg: "TOML cannot represent TypeEmpty"}
+
+	case variant.TypeInt:
+		e.buf.WriteString(strconv.Itoa(v.IntVal()))
+
+	case variant.TypeFloat64:
+		e.buf.WriteString(formatFloat(v.Float64Val()))
+
+	case variant.TypeString:
+		if err := checkString(path, v.StringVal()); err != nil {
+			return err
+		}
+		e.buf.WriteString(formatString(v.StringVal()))
+
+	case variant.TypeBytes:
+		return &encodeError{path: path, msg: "TOML cannot represent TypeBytes"}
+
+	case variant.TypeInt64Array, variant.TypeFloat64Array, variant.TypeStringArray:
+		return e.value(path, v.ToValueList())
+
+	case variant.TypeValueList:
+		list := v.ValueList()
+		if e.version == Version05 {
+			for i := 1; i < len(list); i++ {
+				if arrayKind(list[i].Type()) != arrayKind(list[0].Type()) {
+					return &encodeError{path: path, msg: "mixed-type arrays are not allowed in TOML v0.5.0"}
+				}
+			}
+		}
+		e.buf.WriteByte('[')
+		for i, elem := range list {
+			if i > 0 {
+				e.buf.WriteString(", ")
+			}
+			if err := e.value(path, elem); err != nil {
+				return err
+			}
+		}
+		e.buf.WriteByte(']')
+
+	case variant.TypeKeyValueList:
+		list := v.KeyValueList()
+		if len(list) == 0 {
+			e.buf.WriteString("{}")
+			return nil
+		}
+		if err := checkKeys(path, list); err != nil {
+			return err
+		}
+		e.buf.WriteString("{ ")
+		for i, kv := range list {
+			if i > 0 {
+				e.buf.WriteString(", ")
+			}
+			if err := checkString(path, kv.Key); err != nil {
+				return err
+			}
+			e.key(kv.Key)
+			e.buf.WriteString(" = ")
+			if err := e.value(childPath(path, kv.Key), kv.Value); err != nil {
+				return err
+			}
+		}
+		e.buf.WriteString(" }")
+	}
+	return nil
+}
+
+// arrayKind returns the type that TOML v0.5.0 compares to check that the elements of
+// an array are of one type. All list types except TypeKeyValueList are arrays.
+func arrayKind(t variant.Type) variant.Type {
+	switch t {
+	case variant.TypeInt64Array, variant.TypeFloat64Array, variant.TypeStringArray:
+		return variant.TypeValueList
+	}
+	return t
+}
+
+// childPath returns the path of the key in the table with the specified path.
+func childPath(path []string, key string) []string {
+	return append(path[:len(path):len(path)], key)
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "nan"
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	}
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".e") {
+		// Make sure the number is decoded as float64, not as int.
+		s += ".0"
+	}
+	return s
+}
+
+const hexDigits = "0123456789ABCDEF"
+
+// formatString formats s as a TOML basic string.
+func formatString(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '"':
+			sb.WriteString(`\"`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\b':
+			sb.WriteString(`\b`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\f':
+			sb.WriteString(`\f`)
+		case '\r':
+			sb.WriteString(`\r`)
+		default:
+			if isControl(c) {
+				sb.WriteString(`\u00`)
+				sb.WriteByte(hexDigits[c>>4])
+				sb.WriteByte(hexDigits[c&0xF])
+			} else {
+				sb.WriteByte(c)
+			}
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
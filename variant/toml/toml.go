@@ -0,0 +1,121 @@
+/*
+Package toml implements encoding and decoding of Variant values as TOML documents.
+
+# Decoding
+
+A TOML document is decoded as a TypeKeyValueList. Tables and inline tables are
+decoded as nested TypeKeyValueList, arrays and arrays of tables are decoded as
+TypeValueList. The order of the keys is preserved: keys appear in the order in
+which they are first defined in the document (for tables defined using headers
+this is the position of the first header that mentions the table).
+
+Integers are decoded as TypeInt, floats as TypeFloat64 and strings as
+TypeString. Variant does not have boolean and date/time types, so booleans are
+decoded as TypeString with value "true" or "false" and date/time values are
+decoded as TypeString that contains the date/time exactly as written in the
+document.
+
+Decoding follows TOML v1.0.0 specification.
+
+# Encoding
+
+Only a TypeKeyValueList can be encoded as a TOML document. Nested TypeKeyValueList
+values are encoded as tables and TypeValueList values that contain only
+TypeKeyValueList elements are encoded as arrays of tables. Other lists and key/value
+lists nested in lists are encoded inline.
+
+TOML requires the keys of a table to precede its sub-tables, so the keys of a
+TypeKeyValueList that are encoded inline are written first and the keys that are
+encoded as tables or arrays of tables are written after them. Otherwise the order of
+the keys is preserved. The packed array types are encoded as arrays.
+
+TOML cannot represent TypeEmpty and TypeBytes values and key/value lists that
+contain a key more than once, Encode returns an error if the tree contains them.
+By default the output conforms to TOML v1.0.0. Use
+Encoder.SetVersion to produce TOML v0.5.0, which does not allow arrays that
+contain elements of different types.
+*/
+package toml
+
+import (
+	"io"
+	"io/ioutil"
+
+	"github.com/tigrannajaryan/govariant/variant"
+)
+
+// Version of TOML specification.
+type Version int
+
+const (
+	// Version10 is TOML v1.0.0.
+	Version10 Version = iota
+
+	// Version05 is TOML v0.5.0.
+	Version05
+)
+
+// Unmarshal decodes a TOML document.
+func Unmarshal(data []byte) (variant.Variant, error) {
+	p := newParser(data)
+	if err := p.parse(); err != nil {
+		return variant.Variant{}, err
+	}
+	return p.root.variant(), nil
+}
+
+// Marshal encodes v as a TOML v1.0.0 document. v must be a TypeKeyValueList.
+func Marshal(v variant.Variant) ([]byte, error) {
+	e := encoder{version: Version10}
+	if err := e.document(v); err != nil {
+		return nil, err
+	}
+	return e.buf.Bytes(), nil
+}
+
+// A Decoder reads a TOML document from an input stream.
+type Decoder struct {
+	r io.Reader
+}
+
+// NewDecoder returns a new Decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: r}
+}
+
+// Decode reads the entire input and decodes it as a TOML document.
+func (d *Decoder) Decode() (variant.Variant, error) {
+	data, err := ioutil.ReadAll(d.r)
+	if err != nil {
+		return variant.Variant{}, err
+	}
+	return Unmarshal(data)
+}
+
+// An Encoder writes TOML documents to an output stream.
+type Encoder struct {
+	w       io.Writer
+	version Version
+}
+
+// NewEncoder returns a new Encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w, version: Version10}
+}
+
+// SetVersion sets the version of TOML specification that the output must conform
+// to. The default is Version10.
+func (e *Encoder) SetVersion(version Version) {
+	e.version = version
+}
+
+// Encode writes v as a TOML document. v must be a TypeKeyValueList.
+// Nothing is written if v cannot be expressed in TOML.
+func (e *Encoder) Encode(v variant.Variant) error {
+	enc := encoder{version: e.version}
+	if err := enc.document(v); err != nil {
+		return err
+	}
+	_, err := e.w.Write(enc.buf.Bytes())
+	return err
+}
@@ -0,0 +1,348 @@
+package toml
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tigrannajaryan/govariant/variant"
+)
+
+func TestUnmarshalValues(t *testing.T) {
+	tests := []struct {
+		toml string
+		str  string
+		typ  variant.Type
+	}{
+		{`1`, `1`, variant.TypeInt},
+		{`-17`, `-17`, variant.TypeInt},
+		{`+1_000`, `1000`, variant.TypeInt},
+		{`0xDE_ad`, `57005`, variant.TypeInt},
+		{`0o755`, `493`, variant.TypeInt},
+		{`0b1101`, `13`, variant.TypeInt},
+		{`3.14`, `3.14`, variant.TypeFloat64},
+		{`-0.01`, `-0.01`, variant.TypeFloat64},
+		{`5e+22`, `5e+22`, variant.TypeFloat64},
+		{`6.626e-34`, `6.626e-34`, variant.TypeFloat64},
+		{`224_617.445_991`, `224617.445991`, variant.TypeFloat64},
+		{`inf`, `+Inf`, variant.TypeFloat64},
+		{`-inf`, `-Inf`, variant.TypeFloat64},
+		{`nan`, `NaN`, variant.TypeFloat64},
+		{`true`, `"true"`, variant.TypeString},
+		{`false`, `"false"`, variant.TypeString},
+		{`1979-05-27T07:32:00Z`, `"1979-05-27T07:32:00Z"`, variant.TypeString},
+		{`1979-05-27 07:32:00.999999-07:00`, `"1979-05-27 07:32:00.999999-07:00"`, variant.TypeString},
+		{`1979-05-27`, `"1979-05-27"`, variant.TypeString},
+		{`07:32:00`, `"07:32:00"`, variant.TypeString},
+		{`"a\tb\"\\\u00e9\U0001F600"`, `"a\tb\"\\é😀"`, variant.TypeString},
+		{`'C:\path'`, `"C:\\path"`, variant.TypeString},
+		{"\"\"\"\nline1\nline2\"\"\"", `"line1\nline2"`, variant.TypeString},
+		{"\"\"\"a \\\n    b\"\"\"", `"a b"`, variant.TypeString},
+		{"\"\"\"q\"\"\"\"\"", `"q\"\""`, variant.TypeString},
+		{"'''\nraw \\n\r\n'''", `"raw \\n\n"`, variant.TypeString},
+		{`[]`, `[]`, variant.TypeValueList},
+		{"[1, 'a', [2.5], {x = 1},\n # comment\n]", `[1,"a",[2.5],{"x":1}]`, variant.TypeValueList},
+		{`{}`, `{}`, variant.TypeKeyValueList},
+		{`{ b = 1, a.c = "x" }`, `{"b":1,"a":{"c":"x"}}`, variant.TypeKeyValueList},
+	}
+
+	for _, test := range tests {
+		t.Run(test.toml, func(t *testing.T) {
+			v, err := Unmarshal([]byte("v = " + test.toml))
+			require.NoError(t, err)
+			require.EqualValues(t, 1, v.Len())
+			e := v.KeyValueAt(0).Value
+			assert.EqualValues(t, test.typ, e.Type())
+			assert.EqualValues(t, test.str, e.String())
+		})
+	}
+}
+
+func TestUnmarshalDocument(t *testing.T) {
+	doc := `
+# Comment
+title = "TOML Example"
+"quoted key" = 1
+site."google.com" = true
+
+[owner]
+name = "Tom"
+
+[database.connection]
+ports = [ 8000, 8001 ]
+
+[[products]]
+name = "Hammer"
+
+[[products]]
+
+[[products]]
+name = "Nail"
+color.name = "gray"
+
+[database]
+enabled = true
+
+[[fruits]]
+name = "apple"
+
+[fruits.physical]
+color = "red"
+
+[[fruits.varieties]]
+name = "red delicious"
+
+[[fruits]]
+name = "banana"
+`
+	v, err := Unmarshal([]byte(doc))
+	require.NoError(t, err)
+	assert.EqualValues(
+		t,
+		`{"title":"TOML Example","quoted key":1,"site":{"google.com":"true"},`+
+			`"owner":{"name":"Tom"},`+
+			`"database":{"connection":{"ports":[8000,8001]},"enabled":"true"},`+
+			`"products":[{"name":"Hammer"},{},{"name":"Nail","color":{"name":"gray"}}],`+
+			`"fruits":[{"name":"apple","physical":{"color":"red"},"varieties":[{"name":"red delicious"}]},{"name":"banana"}]}`,
+		v.String(),
+	)
+
+	v, err = Unmarshal(nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, `{}`, v.String())
+
+	v, err = NewDecoder(strings.NewReader("a = 1\r\nb = 2 # c\r\n")).Decode()
+	require.NoError(t, err)
+	assert.EqualValues(t, `{"a":1,"b":2}`, v.String())
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	invalid := []string{
+		"a",
+		"a =",
+		"a = 1 b = 2",
+		"a = 1\na = 2",
+		"= 1",
+		"a = 01",
+		"a = 1.",
+		"a = .5",
+		"a = 1__0",
+		"a = 0x",
+		"a = 9223372036854775808",
+		"a = 1e400",
+		"a = TRUE",
+		"a = \"unterminated",
+		"a = \"a\nb\"",
+		"a = \"\\x\"",
+		"a = \"\\uD800\"",
+		"a = \"a \\\n b\"",
+		"a = 'a\x01'",
+		"a = [1 2]",
+		"a = {b = 1,}",
+		"a = {b = 1\n}",
+		"a = {b = 1}\n[a]",
+		"a = {b = 1}\na.c = 2",
+		"a = [1]\n[[a]]",
+		"[a]\n[a]",
+		"[a]\nb = 1\n[a.b]",
+		"[a.b]\n[[a]]",
+		"[[a]]\n[a]",
+		"[a]\nb.c = 1\n[a.b]",
+		"[a.b.c]\n[a]\nb.d = 1",
+		"a.b = 1\na = 2",
+		"[a] b = 1",
+		"[a",
+		"[[a]",
+		"\"\"\"k\"\"\" = 1",
+		"a = 1\r",
+		"a = \"\xff\"",
+	}
+	for _, s := range invalid {
+		_, err := Unmarshal([]byte(s))
+		assert.Error(t, err, s)
+	}
+
+	_, err := Unmarshal([]byte("a = 1\nb = \n"))
+	assert.EqualError(t, err, `toml: line 2: expected a value, found '\n'`)
+}
+
+func TestMarshal(t *testing.T) {
+	v := variant.NewKeyValueList(
+		[]variant.KeyValue{
+			{Key: "int", Value: variant.NewInt(1)},
+			{Key: "table", Value: variant.NewKeyValueList([]variant.KeyValue{
+				{Key: "a b", Value: variant.NewString("x\ty\"\x01")},
+				{Key: "sub", Value: variant.NewKeyValueList(nil)},
+			})},
+			{Key: "float", Value: variant.NewFloat64(2)},
+			{Key: "ints", Value: variant.NewInt64Array([]int64{1, 2})},
+			{Key: "list", Value: variant.NewValueList([]variant.Variant{
+				variant.NewInt(1), variant.NewString("a"),
+				variant.NewKeyValueList([]variant.KeyValue{{Key: "k", Value: variant.NewFloat64(math.Inf(-1))}}),
+				variant.NewKeyValueList(nil),
+				variant.NewValueList(nil),
+			})},
+			{Key: "tables", Value: variant.NewValueList([]variant.Variant{
+				variant.NewKeyValueList([]variant.KeyValue{{Key: "n", Value: variant.NewInt(1)}}),
+				variant.NewKeyValueList(nil),
+			})},
+			{Key: "", Value: variant.NewString("empty key")},
+		},
+	)
+	// The keys that are encoded inline are written before the tables, e.g. "float"
+	// is written before "table".
+	b, err := Marshal(v)
+	require.NoError(t, err)
+	assert.EqualValues(t, `int = 1
+float = 2.0
+ints = [1, 2]
+list = [1, "a", { k = -inf }, {}, []]
+"" = "empty key"
+
+[table]
+"a b" = "x\ty\"\u0001"
+
+[table.sub]
+
+[[tables]]
+n = 1
+
+[[tables]]
+`, string(b))
+
+	r, err := Unmarshal(b)
+	require.NoError(t, err)
+	assert.EqualValues(
+		t,
+		`{"int":1,"float":2,"ints":[1,2],"list":[1,"a",{"k":-Inf},{},[]],"":"empty key",`+
+			`"table":{"a b":"x\ty\"\x01","sub":{}},"tables":[{"n":1},{}]}`,
+		r.String(),
+	)
+}
+
+func TestMarshalErrors(t *testing.T) {
+	kvl := func(kv ...variant.KeyValue) variant.Variant {
+		return variant.NewKeyValueList(kv)
+	}
+
+	tests := []struct {
+		v   variant.Variant
+		err string
+	}{
+		{variant.NewInt(1), "toml: top-level value must be a KeyValueList, found Int"},
+		{variant.NewValueList(nil), "toml: top-level value must be a KeyValueList, found ValueList"},
+		{
+			kvl(variant.KeyValue{Key: "a", Value: kvl(variant.KeyValue{Key: "b c", Value: variant.NewEmpty()})}),
+			`toml: key a."b c": TOML cannot represent TypeEmpty`,
+		},
+		{
+			kvl(variant.KeyValue{Key: "a", Value: variant.NewValueList([]variant.Variant{variant.NewBytes(nil)})}),
+			`toml: key a: TOML cannot represent TypeBytes`,
+		},
+		{
+			kvl(variant.KeyValue{Key: "a", Value: variant.NewString("\xff")}),
+			`toml: key a: string "\xff" is not valid UTF-8`,
+		},
+		{
+			kvl(variant.KeyValue{Key: "\xff", Value: variant.NewInt(1)}),
+			`toml: string "\xff" is not valid UTF-8`,
+		},
+		{
+			kvl(variant.KeyValue{Key: "a", Value: variant.NewInt(1)}, variant.KeyValue{Key: "a", Value: variant.NewInt(2)}),
+			`toml: key "a" is defined more than once`,
+		},
+		{
+			kvl(variant.KeyValue{Key: "t", Value: kvl()}, variant.KeyValue{Key: "t", Value: variant.NewInt(2)}),
+			`toml: key "t" is defined more than once`,
+		},
+		{
+			kvl(
+				variant.KeyValue{
+					Key: "t",
+					Value: variant.NewValueList([]variant.Variant{
+						kvl(variant.KeyValue{Key: "b", Value: kvl()}, variant.KeyValue{Key: "b", Value: kvl()}),
+					}),
+				},
+			),
+			`toml: key t: key "b" is defined more than once`,
+		},
+		{
+			kvl(
+				variant.KeyValue{
+					Key: "a",
+					Value: variant.NewValueList([]variant.Variant{
+						kvl(variant.KeyValue{Key: "x", Value: variant.NewInt(1)}, variant.KeyValue{Key: "x", Value: variant.NewInt(1)}),
+						variant.NewInt(1),
+					}),
+				},
+			),
+			`toml: key a: key "x" is defined more than once`,
+		},
+	}
+	for _, test := range tests {
+		_, err := Marshal(test.v)
+		assert.EqualError(t, err, test.err)
+	}
+}
+
+func TestEncoderVersion(t *testing.T) {
+	mixed := variant.NewKeyValueList([]variant.KeyValue{
+		{Key: "a", Value: variant.NewValueList([]variant.Variant{variant.NewInt(1), variant.NewFloat64(1)})},
+	})
+	homogeneous := variant.NewKeyValueList([]variant.KeyValue{
+		{Key: "a", Value: variant.NewValueList([]variant.Variant{
+			variant.NewValueList([]variant.Variant{variant.NewInt(1)}),
+			variant.NewValueList([]variant.Variant{variant.NewString("x")}),
+			variant.NewInt64Array([]int64{2}),
+			variant.NewStringArray([]string{"y"}),
+		})},
+	})
+	mixedArray := variant.NewKeyValueList([]variant.KeyValue{
+		{Key: "a", Value: variant.NewValueList([]variant.Variant{
+			variant.NewInt64Array([]int64{1}), variant.NewInt(2),
+		})},
+	})
+
+	var buf bytes.Buffer
+	e := NewEncoder(&buf)
+	require.NoError(t, e.Encode(mixed))
+	assert.EqualValues(t, "a = [1, 1.0]\n", buf.String())
+
+	buf.Reset()
+	e.SetVersion(Version05)
+	assert.EqualError(t, e.Encode(mixed), "toml: key a: mixed-type arrays are not allowed in TOML v0.5.0")
+	assert.EqualValues(t, 0, buf.Len())
+
+	assert.EqualError(t, e.Encode(mixedArray), "toml: key a: mixed-type arrays are not allowed in TOML v0.5.0")
+	assert.EqualValues(t, 0, buf.Len())
+
+	// The packed arrays are arrays like TypeValueList.
+	require.NoError(t, e.Encode(homogeneous))
+	assert.EqualValues(t, "a = [[1], [\"x\"], [2], [\"y\"]]\n", buf.String())
+}
+
+func TestRoundTrip(t *testing.T) {
+	doc := `a = "x"
+b = [1, 2]
+
+[c]
+i = 0
+
+[c.d]
+e = 1.5
+
+[[c.f]]
+g = "h"
+
+[[c.f]]
+`
+	v, err := Unmarshal([]byte(doc))
+	require.NoError(t, err)
+	b, err := Marshal(v)
+	require.NoError(t, err)
+	assert.EqualValues(t, doc, string(b))
+}
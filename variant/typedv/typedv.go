@@ -0,0 +1,146 @@
+//go:build go1.21
+// +build go1.21
+
+/*
+Package typedv implements type-safe generic helpers on top of Variant.
+
+The helpers use the Go type of the value to select the Variant type:
+
+  - int is stored as TypeInt,
+  - float64 is stored as TypeFloat64,
+  - string is stored as TypeString,
+  - []byte is stored as TypeBytes.
+
+The package uses type parameters, which a Go toolchain older than 1.21 does not allow
+in a module that declares an older Go version, so the package is built with Go 1.21
+and newer only. The variant package itself supports older Go versions.
+*/
+package typedv
+
+import (
+	"github.com/tigrannajaryan/govariant/variant"
+)
+
+// Scalar is the set of Go types that can be stored in a Variant directly.
+type Scalar interface {
+	int | float64 | string | []byte
+}
+
+// Of creates a Variant that stores x, e.g. Of(1) is equivalent to variant.NewInt(1).
+// Of does not copy byte slices, see variant.NewBytes.
+func Of[T Scalar](x T) variant.Variant {
+	switch x := any(x).(type) {
+	case int:
+		return variant.NewInt(x)
+	case float64:
+		return variant.NewFloat64(x)
+	case string:
+		return variant.NewString(x)
+	}
+	// []byte is the only remaining type of Scalar.
+	return variant.NewBytes(any(x).([]byte))
+}
+
+// TypeOf returns the Variant type that stores values of the Go type T.
+func TypeOf[T Scalar]() variant.Type {
+	var zero T
+	switch any(zero).(type) {
+	case int:
+		return variant.TypeInt
+	case float64:
+		return variant.TypeFloat64
+	case string:
+		return variant.TypeString
+	}
+	return variant.TypeBytes
+}
+
+// As returns the value stored in v and true if v stores a value of the Go type T, i.e.
+// if v is of type TypeOf[T](). Otherwise returns the zero value and false. Values are
+// not converted between types, e.g. As[float64] returns false for TypeInt.
+func As[T Scalar](v variant.Variant) (T, bool) {
+	var r T
+	if v.Type() != TypeOf[T]() {
+		return r, false
+	}
+	switch p := any(&r).(type) {
+	case *int:
+		*p = v.IntVal()
+	case *float64:
+		*p = v.Float64Val()
+	case *string:
+		*p = v.StringVal()
+	case *[]byte:
+		*p = v.Bytes()
+	}
+	return r, true
+}
+
+// NewValueList creates a Variant of TypeValueList type that contains the elements
+// of xs converted by Of.
+func NewValueList[T Scalar](xs []T) variant.Variant {
+	list := make([]variant.Variant, len(xs))
+	for i, x := range xs {
+		list[i] = Of(x)
+	}
+	return variant.NewValueList(list)
+}
+
+// List is a typed view of a TypeValueList whose elements are values of the Go type
+// T. The view does not copy the list: changes made through Set are visible in the
+// Variant and vice versa.
+type List[T Scalar] struct {
+	list []variant.Variant
+}
+
+// ListOf returns a view of the TypeValueList stored in v and true if all elements of
+// the list are of type TypeOf[T](). Otherwise returns an empty List and false.
+func ListOf[T Scalar](v variant.Variant) (List[T], bool) {
+	if v.Type() != variant.TypeValueList {
+		return List[T]{}, false
+	}
+	list := v.ValueList()
+	t := TypeOf[T]()
+	for i := range list {
+		if list[i].Type() != t {
+			return List[T]{}, false
+		}
+	}
+	return List[T]{list: list}, true
+}
+
+// Len returns the number of elements in the list.
+func (l List[T]) Len() int {
+	return len(l.list)
+}
+
+// At returns the element at the specified index.
+// Will panic if the index is out of bounds or if the element was replaced by a value
+// of a different type after the List was created.
+func (l List[T]) At(i int) T {
+	x, ok := As[T](l.list[i])
+	if !ok {
+		panic(&variant.TypeMismatchError{Want: TypeOf[T](), Got: l.list[i].Type()})
+	}
+	return x
+}
+
+// Set replaces the element at the specified index by x.
+// Will panic if the index is out of bounds.
+func (l List[T]) Set(i int, x T) {
+	l.list[i] = Of(x)
+}
+
+// Slice returns a new slice that contains the elements of the list.
+func (l List[T]) Slice() []T {
+	s := make([]T, len(l.list))
+	for i := range s {
+		s[i] = l.At(i)
+	}
+	return s
+}
+
+// Variant returns the Variant of TypeValueList type that refers to the list.
+func (l List[T]) Variant() variant.Variant {
+	return variant.NewValueList(l.list)
+}
@@ -0,0 +1,86 @@
+//go:build go1.21
+// +build go1.21
+
+package typedv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tigrannajaryan/govariant/variant"
+)
+
+func TestOfAndAs(t *testing.T) {
+	v := Of(123)
+	assert.EqualValues(t, variant.TypeInt, v.Type())
+	i, ok := As[int](v)
+	assert.True(t, ok)
+	assert.EqualValues(t, 123, i)
+
+	v = Of(1.5)
+	assert.EqualValues(t, variant.TypeFloat64, v.Type())
+	f, ok := As[float64](v)
+	assert.True(t, ok)
+	assert.EqualValues(t, 1.5, f)
+
+	v = Of("abc")
+	assert.EqualValues(t, variant.TypeString, v.Type())
+	s, ok := As[string](v)
+	assert.True(t, ok)
+	assert.EqualValues(t, "abc", s)
+
+	v = Of([]byte{1, 2})
+	assert.EqualValues(t, variant.TypeBytes, v.Type())
+	b, ok := As[[]byte](v)
+	assert.True(t, ok)
+	assert.EqualValues(t, []byte{1, 2}, b)
+
+	// Values are not converted.
+	i, ok = As[int](Of(2.0))
+	assert.False(t, ok)
+	assert.EqualValues(t, 0, i)
+	s, ok = As[string](variant.NewEmpty())
+	assert.False(t, ok)
+	assert.EqualValues(t, "", s)
+	b, ok = As[[]byte](Of("a"))
+	assert.False(t, ok)
+	assert.Nil(t, b)
+}
+
+func TestTypeOf(t *testing.T) {
+	assert.EqualValues(t, variant.TypeInt, TypeOf[int]())
+	assert.EqualValues(t, variant.TypeFloat64, TypeOf[float64]())
+	assert.EqualValues(t, variant.TypeString, TypeOf[string]())
+	assert.EqualValues(t, variant.TypeBytes, TypeOf[[]byte]())
+}
+
+func TestList(t *testing.T) {
+	v := NewValueList([]string{"a", "b", "c"})
+	assert.EqualValues(t, `["a","b","c"]`, v.String())
+
+	l, ok := ListOf[string](v)
+	assert.True(t, ok)
+	assert.EqualValues(t, 3, l.Len())
+	assert.EqualValues(t, "b", l.At(1))
+	assert.EqualValues(t, []string{"a", "b", "c"}, l.Slice())
+
+	// The view shares the list with the Variant.
+	l.Set(1, "x")
+	assert.EqualValues(t, `["a","x","c"]`, v.String())
+	assert.EqualValues(t, `["a","x","c"]`, l.Variant().String())
+
+	v.ValueList()[2] = variant.NewInt(1)
+	assert.PanicsWithError(t, "Variant type is Int, want String", func() { l.At(2) })
+	assert.Panics(t, func() { l.At(3) })
+
+	_, ok = ListOf[int](v)
+	assert.False(t, ok)
+	_, ok = ListOf[int](Of(1))
+	assert.False(t, ok)
+
+	l2, ok := ListOf[float64](variant.NewValueList(nil))
+	assert.True(t, ok)
+	assert.EqualValues(t, 0, l2.Len())
+	assert.EqualValues(t, []float64{}, l2.Slice())
+}
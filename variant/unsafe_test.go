@@ -0,0 +1,130 @@
+package variant
+
+import (
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Tests in this file exercise conversions between Variant and strings or slices.
+// Run them with "make test-checkptr" to detect invalid pointer conversions.
+
+func TestVariantStringConversion(t *testing.T) {
+	for _, s := range []string{"", "a", "abcdef", string(make([]byte, 1000))} {
+		v := NewString(s)
+		assert.EqualValues(t, len(s), v.Len())
+		assert.EqualValues(t, s, v.StringVal())
+
+		v = NewStringFromBytes([]byte(s))
+		assert.EqualValues(t, s, v.StringVal())
+	}
+
+	// Empty string stored in a Variant is usable after resizing to zero.
+	v := NewString("abc")
+	v.Resize(0)
+	assert.EqualValues(t, "", v.StringVal())
+}
+
+func TestVariantSliceConversion(t *testing.T) {
+	// Nil slices remain nil.
+	v := NewBytes(nil)
+	assert.Nil(t, v.Bytes())
+	v = NewValueList(nil)
+	assert.Nil(t, v.ValueList())
+	v = NewKeyValueList(nil)
+	assert.Nil(t, v.KeyValueList())
+
+	// Empty non-nil slices remain non-nil.
+	v = NewBytes([]byte{})
+	assert.NotNil(t, v.Bytes())
+	assert.Len(t, v.Bytes(), 0)
+	v = NewValueList([]Variant{})
+	assert.NotNil(t, v.ValueList())
+	v = NewKeyValueList([]KeyValue{})
+	assert.NotNil(t, v.KeyValueList())
+
+	// Len and cap are preserved.
+	b := make([]byte, 3, 10)
+	v = NewBytes(b)
+	assert.EqualValues(t, 3, len(v.Bytes()))
+	assert.EqualValues(t, 10, cap(v.Bytes()))
+	v.Resize(10)
+	assert.EqualValues(t, b[:10], v.Bytes())
+
+	l := make([]Variant, 2, 5)
+	v = NewValueList(l)
+	assert.EqualValues(t, 2, len(v.ValueList()))
+	assert.EqualValues(t, 5, cap(v.ValueList()))
+	v.Resize(5)
+	assert.EqualValues(t, 5, len(v.ValueList()))
+
+	kvl := make([]KeyValue, 1, 4)
+	v = NewKeyValueList(kvl)
+	assert.EqualValues(t, 1, len(v.KeyValueList()))
+	assert.EqualValues(t, 4, cap(v.KeyValueList()))
+	v.Resize(4)
+	assert.EqualValues(t, 4, len(v.KeyValueList()))
+
+	// Slices are aliased, not copied.
+	v = NewBytes(b)
+	v.Bytes()[0] = 1
+	assert.EqualValues(t, 1, b[0])
+
+	v = NewValueList(l)
+	v.ValueList()[1] = NewInt(2)
+	assert.EqualValues(t, 2, l[1].IntVal())
+	e := v.ValueAt(1)
+	assert.EqualValues(t, 2, e.IntVal())
+
+	v = NewKeyValueList(kvl)
+	v.KeyValueAt(0).Key = "k"
+	assert.EqualValues(t, "k", kvl[0].Key)
+}
+
+// createGCTestValue creates a Variant that holds the only references to the
+// allocated strings and slices.
+func createGCTestValue(i int) Variant {
+	s := strconv.Itoa(i)
+	return NewValueList(
+		[]Variant{
+			NewString("str" + s),
+			NewStringFromBytes([]byte("bytes" + s)),
+			NewBytes([]byte(s)),
+			NewKeyValueList([]KeyValue{{Key: "key" + s, Value: NewString("val" + s)}}),
+		},
+	)
+}
+
+func checkGCTestValue(t *testing.T, i int, v Variant) {
+	s := strconv.Itoa(i)
+	l := v.ValueList()
+	assert.EqualValues(t, "str"+s, l[0].StringVal())
+	assert.EqualValues(t, "bytes"+s, l[1].StringVal())
+	assert.EqualValues(t, s, string(l[2].Bytes()))
+	kv := l[3].KeyValueAt(0)
+	assert.EqualValues(t, "key"+s, kv.Key)
+	assert.EqualValues(t, "val"+s, kv.Value.StringVal())
+}
+
+func TestVariantGCKeepsData(t *testing.T) {
+	const count = 1000
+	vals := make([]Variant, count)
+	for i := range vals {
+		vals[i] = createGCTestValue(i)
+	}
+
+	// Create garbage that reuses the memory if the data referenced by
+	// the Variants is incorrectly collected.
+	for j := 0; j < 3; j++ {
+		runtime.GC()
+		for i := 0; i < count; i++ {
+			_ = createGCTestValue(-i)
+		}
+	}
+
+	for i, v := range vals {
+		checkGCTestValue(t, i, v)
+	}
+}
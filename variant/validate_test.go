@@ -0,0 +1,96 @@
+package variant
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	vals := []Variant{
+		NewEmpty(),
+		NewInt(-1),
+		NewFloat64(1.5),
+		NewString(""),
+		NewString("abc"),
+		NewString("a longer string"),
+		NewBytes(nil),
+		NewBytes(make([]byte, 1, 10)),
+		NewValueList(nil),
+		NewValueList([]Variant{NewInt(1), NewValueList([]Variant{NewString("abc")})}),
+		NewKeyValueList([]KeyValue{{Key: "k", Value: NewKeyValueList(nil)}}),
+	}
+	for _, v := range vals {
+		assert.NoError(t, v.Validate(), v.String())
+	}
+
+	// A list that refers to the elements of its parent without containing itself.
+	list := make([]Variant, 2)
+	list[0] = NewInt(1)
+	list[1] = NewValueList(list[:1])
+	v := NewValueList(list)
+	assert.NoError(t, v.Validate())
+}
+
+func TestValidateInvalid(t *testing.T) {
+	b := []byte("abc")
+	tests := []struct {
+		v   Variant
+		err string
+	}{
+		{
+			Variant{lenAndType: 10},
+			"invalid Variant: invalid type 10",
+		},
+		{
+			Variant{lenAndType: int(TypeEmpty), capOrVal: 1},
+			"invalid Variant: Empty has non-zero value",
+		},
+		{
+			Variant{ptr: unsafe.Pointer(&b[0]), lenAndType: int(TypeInt)},
+			"invalid Variant: Int has non-zero len or pointer",
+		},
+		{
+			Variant{lenAndType: 10<<typeFieldBitCount | int(TypeString)},
+			"invalid Variant: String of len 10 has nil pointer",
+		},
+		{
+			Variant{ptr: unsafe.Pointer(&b[0]), lenAndType: 1<<typeFieldBitCount | int(TypeString), capOrVal: 1},
+			"invalid Variant: String has non-zero capacity",
+		},
+		{
+			Variant{lenAndType: -1<<typeFieldBitCount | int(TypeString)},
+			"invalid Variant: String has negative len -1",
+		},
+		{
+			Variant{ptr: unsafe.Pointer(&b[0]), lenAndType: 4<<typeFieldBitCount | int(TypeBytes), capOrVal: 3},
+			"invalid Variant: Bytes len 4 exceeds capacity 3",
+		},
+		{
+			Variant{lenAndType: 1<<typeFieldBitCount | int(TypeValueList), capOrVal: 1},
+			"invalid Variant: ValueList of len 1 has nil pointer",
+		},
+		{
+			NewValueList([]Variant{NewInt(1), NewKeyValueList([]KeyValue{{Key: "k", Value: Variant{lenAndType: 10}}})}),
+			"invalid Variant at [1][0].Value: invalid type 10",
+		},
+	}
+	for _, test := range tests {
+		err := test.v.Validate()
+		if assert.Error(t, err) {
+			assert.EqualValues(t, test.err, err.Error())
+		}
+	}
+}
+
+func TestValidateCycle(t *testing.T) {
+	list := make([]Variant, 2)
+	list[0] = NewInt(1)
+	list[1] = NewValueList(list)
+	v := NewValueList(list)
+	err := v.Validate()
+	if assert.Error(t, err) {
+		assert.EqualValues(t, "invalid Variant at [1][1]: list contains itself", err.Error())
+	}
+}
@@ -30,18 +30,15 @@ func TestVariantNewBytesTooLarge(t *testing.T) {
 	})
 }
 
-func TestVariantNewValueList(t *testing.T) {
-	assert.Panics(t, func() {
-		b := make([]Variant, maxSliceLen+1)
-		NewValueList(b)
-	})
-}
-
-func TestVariantNewKeyValueList(t *testing.T) {
-	assert.Panics(t, func() {
-		b := make([]KeyValue, maxSliceLen+1)
-		NewKeyValueList(b)
-	})
+// The lists of maxSliceLen+1 elements do not fit in memory, so the length limit of
+// NewValueList, NewKeyValueList and the array constructors is tested with checkLen,
+// which they call before accessing the elements.
+func TestCheckLen(t *testing.T) {
+	assert.NotPanics(t, func() { checkLen(maxSliceLen) })
+	assert.PanicsWithError(
+		t, (&LengthError{Len: maxSliceLen + 1, Max: maxSliceLen}).Error(),
+		func() { checkLen(maxSliceLen + 1) },
+	)
 }
 
 func TestVariantResizeTooLarge(t *testing.T) {
@@ -1,11 +1,10 @@
-// +build amd64
+// +build amd64 arm64
 
 package variant
 
-// This file contains Variant implementation specific to GOARCH=amd64
+// This file contains Variant implementation specific to GOARCH=amd64 and GOARCH=arm64
 
 import (
-	"reflect"
 	"unsafe"
 )
 
@@ -28,72 +27,27 @@ type Variant struct {
 	capOrVal int
 }
 
-// NewInt creates a Variant of TypeInt type.
-func NewInt(v int) Variant {
+// The functions below are the encoding primitives that depend on the size of
+// capOrVal. See variant.go for the rest of the primitives.
+
+func newInt(v int) Variant {
 	return Variant{
 		lenAndType: int(TypeInt),
 		capOrVal:   v,
 	}
 }
 
-// NewFloat64 creates a Variant of TypeFloat64 type.
-func NewFloat64(v float64) Variant {
+func newFloat64(v float64) Variant {
 	return Variant{
 		lenAndType: int(TypeFloat64),
 		capOrVal:   *(*int)(unsafe.Pointer(&v)),
 	}
 }
 
-// NewBytes creates a Variant of TypeBytes type and initializes it with the specified
-// slice of bytes.
-//
-// This function does not copy the slice. The Variant will point to
-// the same slice that is pointed to by the parameter v. Any changes made to the bytes
-// in the slice v will be also reflected in the byte slice stored in this Variant.
-func NewBytes(v []byte) Variant {
-	hdr := (*reflect.SliceHeader)(unsafe.Pointer(&v))
-	if hdr.Len > maxSliceLen {
-		panic("maximum len exceeded")
-	}
-
-	return Variant{
-		ptr:        unsafe.Pointer(hdr.Data),
-		lenAndType: (hdr.Len << typeFieldBitCount) | int(TypeBytes),
-		capOrVal:   hdr.Cap,
-	}
-}
-
-// NewValueList creates a Variant of TypeValueList type and initializes it with the
-// specified slice of Variants.
-//
-// This function does not copy the slice. The Variant will point to the same slice that
-// is pointed to by the parameter v. Any changes made to the elements in the slice v
-// will be also reflected in the list stored in this Variant.
-func NewValueList(v []Variant) Variant {
-	hdr := (*reflect.SliceHeader)(unsafe.Pointer(&v))
-	if hdr.Len > maxSliceLen {
-		panic("maximum len exceeded")
-	}
-
-	return Variant{
-		ptr:        unsafe.Pointer(hdr.Data),
-		lenAndType: (hdr.Len << typeFieldBitCount) | int(TypeValueList),
-		capOrVal:   hdr.Cap,
-	}
-}
-
-// NewKeyValueList creates a Variant of TypeKeyValueList type and initializes it with the
-// specified slice of KeyValues.
-//
-// This function does not copy the slice. The Variant will point to the same slice that
-// is pointed to by the parameter v. Any changes made to the elements in the slice v
-// will be also reflected in the list stored in this Variant.
-func NewKeyValueList(v []KeyValue) Variant {
-	hdr := (*reflect.SliceHeader)(unsafe.Pointer(&v))
-
+func newSlice(p unsafe.Pointer, len, cap int, t Type) Variant {
 	return Variant{
-		ptr:        unsafe.Pointer(hdr.Data),
-		lenAndType: (hdr.Len << typeFieldBitCount) | int(TypeKeyValueList),
-		capOrVal:   hdr.Cap,
+		ptr:        p,
+		lenAndType: (len << typeFieldBitCount) | int(t),
+		capOrVal:   cap,
 	}
 }
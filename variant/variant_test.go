@@ -2,7 +2,6 @@ package variant
 
 import (
 	"fmt"
-	"reflect"
 	"runtime"
 	"strconv"
 	"testing"
@@ -13,27 +12,57 @@ import (
 	"github.com/tigrannajaryan/govariant/internal/testutil"
 )
 
-func TestVariantFieldAliasing(t *testing.T) {
-	v := Variant{}
+func TestType(t *testing.T) {
+	names := map[Type]string{
+		TypeEmpty:        "Empty",
+		TypeInt:          "Int",
+		TypeFloat64:      "Float64",
+		TypeString:       "String",
+		TypeBytes:        "Bytes",
+		TypeValueList:    "ValueList",
+		TypeKeyValueList: "KeyValueList",
+		TypeInt64Array:   "Int64Array",
+		TypeFloat64Array: "Float64Array",
+		TypeStringArray:  "StringArray",
+	}
+	for typ, name := range names {
+		assert.EqualValues(t, name, typ.String())
 
-	// Ensure fields correctly alias corresponding fields of StringHeader
+		parsed, err := ParseType(name)
+		assert.NoError(t, err)
+		assert.EqualValues(t, typ, parsed)
 
-	// Data/ptr field.
-	assert.EqualValues(t, unsafe.Sizeof(reflect.StringHeader{}.Data), unsafe.Sizeof(v.ptr))
+		text, err := typ.MarshalText()
+		assert.NoError(t, err)
+		assert.EqualValues(t, name, string(text))
 
-	// Len field.
-	assert.EqualValues(t, unsafe.Sizeof(reflect.StringHeader{}.Len), unsafe.Sizeof(v.lenAndType))
+		var unmarshaled Type
+		assert.NoError(t, unmarshaled.UnmarshalText(text))
+		assert.EqualValues(t, typ, unmarshaled)
+	}
+
+	assert.EqualValues(t, "Type(10)", Type(10).String())
+	assert.EqualValues(t, "Type(-1)", Type(-1).String())
+	assert.EqualValues(t, "Int", fmt.Sprintf("%v", TypeInt))
 
-	// Ensure fields correctly alias corresponding fields of SliceHeader
+	_, err := Type(10).MarshalText()
+	assert.EqualError(t, err, "cannot marshal invalid Variant type 10")
 
-	// Data/ptr field.
-	assert.EqualValues(t, unsafe.Sizeof(reflect.SliceHeader{}.Data), unsafe.Sizeof(v.ptr))
+	for _, name := range []string{"", "int", "TypeInt", "Type(10)"} {
+		_, err := ParseType(name)
+		assert.EqualError(t, err, fmt.Sprintf("unknown Variant type name %q", name))
+	}
+	var typ Type
+	assert.Error(t, typ.UnmarshalText([]byte("Foo")))
+}
 
-	// Len field.
-	assert.EqualValues(t, unsafe.Sizeof(reflect.SliceHeader{}.Len), unsafe.Sizeof(v.lenAndType))
+func TestVariantFieldAliasing(t *testing.T) {
+	v := Variant{}
 
-	// Cap field.
-	assert.True(t, unsafe.Sizeof(reflect.SliceHeader{}.Cap) <= unsafe.Sizeof(v.capOrVal))
+	// Ensure fields can hold the pointer, the length and the capacity of a slice.
+	assert.EqualValues(t, unsafe.Sizeof(uintptr(0)), unsafe.Sizeof(v.ptr))
+	assert.EqualValues(t, unsafe.Sizeof(int(0)), unsafe.Sizeof(v.lenAndType))
+	assert.True(t, unsafe.Sizeof(int(0)) <= unsafe.Sizeof(v.capOrVal))
 
 	// Ensure float64 can correctly fit in capOrVal
 	assert.EqualValues(t, unsafe.Sizeof(float64(0.0)), unsafe.Sizeof(v.capOrVal))
@@ -159,7 +188,6 @@ func TestVariantGC(t *testing.T) {
 	var v1 Variant
 	s1 := strconv.Itoa(1234)
 	v1 = NewString(s1)
-	v1 = v1
 
 	for i := 0; i < 10000; i++ {
 		s1 := strconv.Itoa(i)
@@ -185,7 +213,8 @@ func TestVariantGC(t *testing.T) {
 	runtime.ReadMemStats(&ms)
 
 	s2 = v.StringVal()
-	s2 = s2
+	assert.EqualValues(t, "1234", s2)
+	assert.EqualValues(t, "1234", v1.StringVal())
 }
 
 func TestVariantPanics(t *testing.T) {
@@ -208,6 +237,72 @@ func TestVariantPanics(t *testing.T) {
 	}
 }
 
+func TestCheckedAccessors(t *testing.T) {
+	b := []byte{1, 2}
+	list := []Variant{NewInt(1)}
+	kvl := []KeyValue{{Key: "k", Value: NewInt(1)}}
+	vals := []Variant{
+		NewEmpty(),
+		NewInt(123),
+		NewFloat64(1.5),
+		NewString("abc"),
+		NewBytes(b),
+		NewValueList(list),
+		NewKeyValueList(kvl),
+	}
+	for _, v := range vals {
+		t.Run(v.String(), func(t *testing.T) {
+			i, ok := v.Int()
+			assert.EqualValues(t, v.Type() == TypeInt, ok)
+			if ok {
+				assert.EqualValues(t, 123, i)
+			} else {
+				assert.EqualValues(t, 0, i)
+			}
+
+			f, ok := v.Float64()
+			assert.EqualValues(t, v.Type() == TypeFloat64, ok)
+			if ok {
+				assert.EqualValues(t, 1.5, f)
+			} else {
+				assert.EqualValues(t, 0, f)
+			}
+
+			s, ok := v.Str()
+			assert.EqualValues(t, v.Type() == TypeString, ok)
+			if ok {
+				assert.EqualValues(t, "abc", s)
+			} else {
+				assert.EqualValues(t, "", s)
+			}
+
+			bv, ok := v.BytesOk()
+			assert.EqualValues(t, v.Type() == TypeBytes, ok)
+			if ok {
+				assert.EqualValues(t, b, bv)
+			} else {
+				assert.Nil(t, bv)
+			}
+
+			lv, ok := v.ListOk()
+			assert.EqualValues(t, v.Type() == TypeValueList, ok)
+			if ok {
+				assert.EqualValues(t, list, lv)
+			} else {
+				assert.Nil(t, lv)
+			}
+
+			kv, ok := v.MapOk()
+			assert.EqualValues(t, v.Type() == TypeKeyValueList, ok)
+			if ok {
+				assert.EqualValues(t, kvl, kv)
+			} else {
+				assert.Nil(t, kv)
+			}
+		})
+	}
+}
+
 func TestResize(t *testing.T) {
 	v := NewBytes([]byte("abc"))
 	assert.EqualValues(t, 3, v.Len())
@@ -223,6 +318,42 @@ func TestResize(t *testing.T) {
 
 	assert.Panics(t, func() { v.Resize(-1) })
 	assert.Panics(t, func() { v.Resize(4) })
+
+	for _, s := range []string{"abc", "Hello, World"} {
+		v = NewString(s)
+		assert.Panics(t, func() { v.Resize(1) })
+		v.Resize(0)
+		assert.EqualValues(t, 0, v.Len())
+		assert.EqualValues(t, "", v.StringVal())
+	}
+}
+
+func TestNewBytesClipped(t *testing.T) {
+	b := make([]byte, 3, 10)
+	v := NewBytesClipped(b)
+	assert.EqualValues(t, 3, v.Len())
+	assert.EqualValues(t, 3, cap(v.Bytes()))
+	assert.Panics(t, func() { v.Resize(4) })
+
+	// Appending reallocates instead of overwriting the bytes after the end of b.
+	_ = append(v.Bytes(), 1)
+	assert.EqualValues(t, 0, b[:4][3])
+}
+
+func TestStringValDoesNotChange(t *testing.T) {
+	// The returned strings reference the original bytes, not the memory of the
+	// Variant, so they do not change when the Variant is reassigned.
+	var strs []string
+	for _, e := range []Variant{NewString("a"), NewString("b"), NewString("c")} {
+		strs = append(strs, e.StringVal())
+	}
+	assert.EqualValues(t, []string{"a", "b", "c"}, strs)
+
+	v := NewString("abc")
+	s := v.StringVal()
+	v = NewString("def")
+	assert.EqualValues(t, "abc", s)
+	assert.EqualValues(t, "def", v.StringVal())
 }
 
 func createVariantInt() Variant {
@@ -0,0 +1,167 @@
+package variant
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalk(t *testing.T) {
+	v := MustParse(`[1,{"a":"x","b":[2,3]},[]]`)
+
+	var visits []string
+	err := Walk(
+		&v,
+		func(path Path, v *Variant) error {
+			visits = append(visits, "pre "+path.String()+" "+v.String())
+			return nil
+		},
+		func(path Path, v *Variant) error {
+			visits = append(visits, "post "+path.String())
+			return nil
+		},
+	)
+	assert.NoError(t, err)
+	assert.EqualValues(
+		t,
+		[]string{
+			`pre  [1,{"a":"x","b":[2,3]},[]]`,
+			`pre [0] 1`,
+			`post [0]`,
+			`pre [1] {"a":"x","b":[2,3]}`,
+			`pre [1]["a"] "x"`,
+			`post [1]["a"]`,
+			`pre [1]["b"] [2,3]`,
+			`pre [1]["b"][0] 2`,
+			`post [1]["b"][0]`,
+			`pre [1]["b"][1] 3`,
+			`post [1]["b"][1]`,
+			`post [1]["b"]`,
+			`post [1]`,
+			`pre [2] []`,
+			`post [2]`,
+			`post `,
+		},
+		visits,
+	)
+}
+
+func TestWalkPath(t *testing.T) {
+	v := MustParse(`{"":[0,1]}`)
+	var paths []Path
+	err := Walk(&v, nil, func(path Path, v *Variant) error {
+		paths = append(paths, append(Path(nil), path...))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(
+		t,
+		[]Path{
+			{{Index: 0, Key: "", HasKey: true}, {Index: 0}},
+			{{Index: 0, Key: "", HasKey: true}, {Index: 1}},
+			{{Index: 0, Key: "", HasKey: true}},
+			nil,
+		},
+		paths,
+	)
+	assert.EqualValues(t, `[""][1]`, paths[1].String())
+}
+
+func TestWalkSkipChildren(t *testing.T) {
+	v := MustParse(`[[1,2],[3]]`)
+	var visited []string
+	err := Walk(
+		&v,
+		func(path Path, v *Variant) error {
+			visited = append(visited, path.String())
+			if path.String() == "[0]" {
+				return SkipChildren
+			}
+			return nil
+		},
+		func(path Path, v *Variant) error {
+			// SkipChildren returned by post is an error.
+			if path.String() == "[1]" {
+				return SkipChildren
+			}
+			return nil
+		},
+	)
+	assert.Equal(t, SkipChildren, err)
+	assert.EqualValues(t, []string{"", "[0]", "[1]", "[1][0]"}, visited)
+}
+
+func TestWalkStop(t *testing.T) {
+	v := MustParse(`[1,2,3]`)
+	count := 0
+	pre := func(path Path, v *Variant) error {
+		count++
+		if v.Type() == TypeInt && v.IntVal() == 2 {
+			return SkipAll
+		}
+		return nil
+	}
+	assert.NoError(t, Walk(&v, pre, nil))
+	assert.EqualValues(t, 3, count)
+
+	errStop := errors.New("stop")
+	count = 0
+	post := func(path Path, v *Variant) error {
+		count++
+		return errStop
+	}
+	assert.Equal(t, errStop, Walk(&v, nil, post))
+	assert.EqualValues(t, 1, count)
+
+	// SkipChildren from the pre-order function of the root.
+	count = 0
+	assert.NoError(t, Walk(&v, func(Path, *Variant) error { return SkipChildren }, func(Path, *Variant) error {
+		count++
+		return nil
+	}))
+	assert.EqualValues(t, 1, count)
+}
+
+func TestWalkReplace(t *testing.T) {
+	v := MustParse(`{"password":"secret","user":{"name":"x","password":"y"},"list":[5]}`)
+	err := Walk(&v, func(path Path, v *Variant) error {
+		switch {
+		case len(path) > 0 && path[len(path)-1].Key == "password":
+			*v = NewString("***")
+		case path.String() == `["list"]`:
+			// The children of the new value are visited.
+			*v = NewValueList([]Variant{NewInt(1), NewValueList([]Variant{NewString("z")})})
+		case v.Type() == TypeInt:
+			*v = NewInt(v.IntVal() * 10)
+		}
+		return nil
+	}, nil)
+	assert.NoError(t, err)
+	assert.EqualValues(t, `{"password":"***","user":{"name":"x","password":"***"},"list":[10,["z"]]}`, v.String())
+
+	// Replacing the root.
+	v = NewInt(1)
+	assert.NoError(t, Walk(&v, func(path Path, v *Variant) error {
+		*v = NewString("root")
+		return nil
+	}, nil))
+	assert.EqualValues(t, `"root"`, v.String())
+}
+
+func TestWalkDeep(t *testing.T) {
+	// Walk does not recurse, so the depth of the tree is limited only by the memory.
+	const depth = 100000
+	v := NewInt(1)
+	for i := 0; i < depth; i++ {
+		v = NewValueList([]Variant{v})
+	}
+	maxLen := 0
+	assert.NoError(t, Walk(&v, func(path Path, v *Variant) error {
+		if len(path) > maxLen {
+			maxLen = len(path)
+		}
+		return nil
+	}, nil))
+	assert.EqualValues(t, depth, maxLen)
+}
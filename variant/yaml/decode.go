@@ -0,0 +1,275 @@
+package yaml
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/tigrannajaryan/govariant/variant"
+)
+
+// DefaultMaxAliasNodes is the default limit of the number of nodes that can be
+// created by alias expansion when decoding one YAML document.
+const DefaultMaxAliasNodes = 100000
+
+// A Decoder reads Variants from a stream of YAML documents.
+type Decoder struct {
+	d             *yamlv3.Decoder
+	maxAliasNodes int
+}
+
+// NewDecoder returns a new Decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{d: yamlv3.NewDecoder(r), maxAliasNodes: DefaultMaxAliasNodes}
+}
+
+// SetMaxAliasNodes sets the maximum number of nodes that can be created by alias
+// expansion when decoding one document. If the limit is exceeded Decode returns
+// an error. The default is DefaultMaxAliasNodes.
+func (d *Decoder) SetMaxAliasNodes(n int) {
+	d.maxAliasNodes = n
+}
+
+// Decode reads the next YAML document from the stream and returns it.
+// Returns io.EOF if there are no more documents in the stream.
+func (d *Decoder) Decode() (variant.Variant, error) {
+	var n yamlv3.Node
+	if err := d.d.Decode(&n); err != nil {
+		return variant.Variant{}, err
+	}
+	c := converter{maxAliasNodes: d.maxAliasNodes, expanding: map[*yamlv3.Node]bool{}}
+	return c.convert(&n)
+}
+
+// converter converts a tree of YAML nodes to a Variant.
+type converter struct {
+	// Number of nodes created so far by alias expansion and the limit.
+	aliasNodes    int
+	maxAliasNodes int
+
+	// The anchored nodes that are currently being expanded. Used for detecting
+	// recursive aliases.
+	expanding map[*yamlv3.Node]bool
+}
+
+var errAliasLimit = errors.New("yaml: document contains too many alias expansions")
+
+func nodeError(n *yamlv3.Node, format string, args ...interface{}) error {
+	return fmt.Errorf("yaml: line %d: %s", n.Line, fmt.Sprintf(format, args...))
+}
+
+func (c *converter) convert(n *yamlv3.Node) (variant.Variant, error) {
+	if len(c.expanding) > 0 {
+		c.aliasNodes++
+		if c.aliasNodes > c.maxAliasNodes {
+			return variant.Variant{}, errAliasLimit
+		}
+	}
+
+	switch n.Kind {
+	case yamlv3.DocumentNode:
+		if len(n.Content) == 0 {
+			return variant.NewEmpty(), nil
+		}
+		return c.convert(n.Content[0])
+
+	case yamlv3.AliasNode:
+		if c.expanding[n.Alias] {
+			return variant.Variant{}, nodeError(n, "recursive alias %q", n.Value)
+		}
+		c.expanding[n.Alias] = true
+		v, err := c.convert(n.Alias)
+		delete(c.expanding, n.Alias)
+		return v, err
+
+	case yamlv3.ScalarNode:
+		return scalar(n)
+
+	case yamlv3.SequenceNode:
+		if n.Style&yamlv3.TaggedStyle != 0 && n.Tag != "!!seq" {
+			return variant.Variant{}, nodeError(n, "unsupported sequence tag %s", n.Tag)
+		}
+		list := make([]variant.Variant, len(n.Content))
+		for i, e := range n.Content {
+			var err error
+			if list[i], err = c.convert(e); err != nil {
+				return variant.Variant{}, err
+			}
+		}
+		return variant.NewValueList(list), nil
+
+	case yamlv3.MappingNode:
+		if n.Style&yamlv3.TaggedStyle != 0 && n.Tag != "!!map" {
+			return variant.Variant{}, nodeError(n, "unsupported mapping tag %s", n.Tag)
+		}
+		list := make([]variant.KeyValue, len(n.Content)/2)
+		keys := make(map[string]bool, len(list))
+		for i := range list {
+			k := n.Content[2*i]
+			if k.Kind == yamlv3.AliasNode {
+				k = k.Alias
+			}
+			if k.Kind != yamlv3.ScalarNode {
+				return variant.Variant{}, nodeError(k, "mapping key must be a scalar")
+			}
+			if keys[k.Value] {
+				return variant.Variant{}, nodeError(k, "duplicate mapping key %q", k.Value)
+			}
+			keys[k.Value] = true
+			list[i].Key = k.Value
+
+			var err error
+			if list[i].Value, err = c.convert(n.Content[2*i+1]); err != nil {
+				return variant.Variant{}, err
+			}
+		}
+		return variant.NewKeyValueList(list), nil
+	}
+	return variant.Variant{}, nodeError(n, "unsupported node kind %d", n.Kind)
+}
+
+// scalar converts a scalar node to a Variant. Untagged plain scalars are resolved
+// according to YAML 1.2 core schema, other untagged scalars are strings.
+func scalar(n *yamlv3.Node) (variant.Variant, error) {
+	if n.Style&yamlv3.TaggedStyle == 0 {
+		if n.Style != 0 {
+			// Quoted, literal or folded scalar.
+			return variant.NewString(n.Value), nil
+		}
+		return resolve(n.Value), nil
+	}
+
+	switch n.Tag {
+	case "!!str":
+		return variant.NewString(n.Value), nil
+	case "!!null":
+		if !isNull(n.Value) {
+			return variant.Variant{}, nodeError(n, "invalid !!null value %q", n.Value)
+		}
+		return variant.NewEmpty(), nil
+	case "!!bool":
+		if !isBool(n.Value) {
+			return variant.Variant{}, nodeError(n, "invalid !!bool value %q", n.Value)
+		}
+		return variant.NewString(strings.ToLower(n.Value)), nil
+	case "!!int":
+		if v, ok := parseInt(n.Value); ok {
+			return v, nil
+		}
+		return variant.Variant{}, nodeError(n, "invalid !!int value %q", n.Value)
+	case "!!float":
+		if v, ok := parseInt(n.Value); ok && v.Type() == variant.TypeInt {
+			return variant.NewFloat64(float64(v.IntVal())), nil
+		}
+		if f, ok := parseFloat(n.Value); ok {
+			return variant.NewFloat64(f), nil
+		}
+		return variant.Variant{}, nodeError(n, "invalid !!float value %q", n.Value)
+	case "!!binary":
+		b, err := base64.StdEncoding.DecodeString(removeSpaces(n.Value))
+		if err != nil {
+			return variant.Variant{}, nodeError(n, "invalid !!binary value: %v", err)
+		}
+		return variant.NewBytes(b), nil
+	}
+	return variant.Variant{}, nodeError(n, "unsupported scalar tag %s", n.Tag)
+}
+
+func removeSpaces(s string) string {
+	return strings.Map(
+		func(r rune) rune {
+			switch r {
+			case ' ', '\t', '\r', '\n':
+				return -1
+			}
+			return r
+		}, s,
+	)
+}
+
+// Regular expressions of YAML 1.2 core schema, see
+// https://yaml.org/spec/1.2.2/#1032-tag-resolution
+var (
+	decIntRe = regexp.MustCompile(`^[-+]?[0-9]+$`)
+	octIntRe = regexp.MustCompile(`^0o[0-7]+$`)
+	hexIntRe = regexp.MustCompile(`^0x[0-9a-fA-F]+$`)
+	floatRe  = regexp.MustCompile(`^[-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?$`)
+)
+
+// resolve converts an untagged plain scalar to a Variant.
+func resolve(s string) variant.Variant {
+	if isNull(s) {
+		return variant.NewEmpty()
+	}
+	if isBool(s) {
+		return variant.NewString(strings.ToLower(s))
+	}
+	if v, ok := parseInt(s); ok {
+		return v
+	}
+	if f, ok := parseFloat(s); ok {
+		return variant.NewFloat64(f)
+	}
+	return variant.NewString(s)
+}
+
+func isNull(s string) bool {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return true
+	}
+	return false
+}
+
+func isBool(s string) bool {
+	switch s {
+	case "true", "True", "TRUE", "false", "False", "FALSE":
+		return true
+	}
+	return false
+}
+
+// parseInt parses an integer in one of the core schema forms. Decimal integers
+// that do not fit into int are returned as TypeFloat64.
+func parseInt(s string) (variant.Variant, bool) {
+	switch {
+	case decIntRe.MatchString(s):
+		i, err := strconv.ParseInt(s, 10, 0)
+		if err == nil {
+			return variant.NewInt(int(i)), true
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		return variant.NewFloat64(f), err == nil
+	case octIntRe.MatchString(s):
+		i, err := strconv.ParseInt(s[2:], 8, 0)
+		return variant.NewInt(int(i)), err == nil
+	case hexIntRe.MatchString(s):
+		i, err := strconv.ParseInt(s[2:], 16, 0)
+		return variant.NewInt(int(i)), err == nil
+	}
+	return variant.Variant{}, false
+}
+
+func parseFloat(s string) (float64, bool) {
+	switch s {
+	case ".inf", ".Inf", ".INF", "+.inf", "+.Inf", "+.INF":
+		return math.Inf(1), true
+	case "-.inf", "-.Inf", "-.INF":
+		return math.Inf(-1), true
+	case ".nan", ".NaN", ".NAN":
+		return math.NaN(), true
+	}
+	if !floatRe.MatchString(s) {
+		return 0, false
+	}
+	// Out of range values are returned as infinity.
+	f, err := strconv.ParseFloat(s, 64)
+	return f, err == nil || errors.Is(err, strconv.ErrRange)
+}
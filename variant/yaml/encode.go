@@ -0,0 +1,89 @@
+package yaml
+
+import (
+	"encoding/base64"
+	"math"
+	"strconv"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/tigrannajaryan/govariant/variant"
+)
+
+// encodeNode converts a Variant to a tree of YAML nodes.
+func encodeNode(v variant.Variant) *yamlv3.Node {
+	switch v.Type() {
+	case variant.TypeEmpty:
+		return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!null", Value: "null"}
+
+	case variant.TypeInt:
+		return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!int", Value: strconv.Itoa(v.IntVal())}
+
+	case variant.TypeFloat64:
+		return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!float", Value: formatFloat(v.Float64Val())}
+
+	case variant.TypeString:
+		n := &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: v.StringVal()}
+		r := resolve(n.Value)
+		if r.Type() != variant.TypeString || isBool(n.Value) || strings.ContainsRune(n.Value, '\n') {
+			// Quote the strings that would be otherwise decoded as a different type
+			// or value. Multi-line strings are also quoted to guarantee exact round
+			// trip of the whitespace.
+			n.Style = yamlv3.DoubleQuotedStyle
+		}
+		return n
+
+	case variant.TypeBytes:
+		return &yamlv3.Node{
+			Kind:  yamlv3.ScalarNode,
+			Tag:   "!!binary",
+			Style: yamlv3.TaggedStyle,
+			Value: base64.StdEncoding.EncodeToString(v.Bytes()),
+		}
+
+	case variant.TypeValueList:
+		list := v.ValueList()
+		n := &yamlv3.Node{Kind: yamlv3.SequenceNode, Tag: "!!seq", Content: make([]*yamlv3.Node, len(list))}
+		if len(list) == 0 {
+			n.Style = yamlv3.FlowStyle
+		}
+		for i, e := range list {
+			n.Content[i] = encodeNode(e)
+		}
+		return n
+
+	case variant.TypeKeyValueList:
+		list := v.KeyValueList()
+		n := &yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map", Content: make([]*yamlv3.Node, 2*len(list))}
+		if len(list) == 0 {
+			n.Style = yamlv3.FlowStyle
+		}
+		for i, kv := range list {
+			n.Content[2*i] = encodeNode(variant.NewString(kv.Key))
+			n.Content[2*i+1] = encodeNode(kv.Value)
+		}
+		return n
+
+	case variant.TypeInt64Array, variant.TypeFloat64Array, variant.TypeStringArray:
+		return encodeNode(v.ToValueList())
+	}
+	panic(&variant.InvalidTypeError{Type: v.Type()})
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return ".nan"
+	case math.IsInf(f, 1):
+		return ".inf"
+	case math.IsInf(f, -1):
+		return "-.inf"
+	}
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".e") {
+		// Make sure the number is decoded as float64, not as int.
+		s += ".0"
+	}
+	return s
+}
@@ -0,0 +1,95 @@
+/*
+Package yaml implements encoding and decoding of Variant values as YAML documents.
+
+# Decoding
+
+YAML mappings are decoded as TypeKeyValueList preserving the order of the keys,
+sequences are decoded as TypeValueList. Untagged plain scalars are resolved
+according to YAML 1.2 core schema:
+
+  - null, Null, NULL, ~ and empty scalar are decoded as TypeEmpty,
+  - true, True, TRUE, false, False, FALSE are decoded as TypeString with value
+    "true" or "false" (Variant does not have a boolean type),
+  - decimal integers, 0o-prefixed octal and 0x-prefixed hexadecimal integers
+    are decoded as TypeInt. Decimal integers that do not fit into int are
+    decoded as TypeFloat64, octal and hexadecimal ones as TypeString,
+  - decimal floating point numbers, .inf, -.inf and .nan in any of the
+    allowed spellings are decoded as TypeFloat64,
+  - all other scalars are decoded as TypeString.
+
+Quoted and block scalars are always decoded as TypeString. Scalars with explicit
+!!str, !!int, !!float, !!null and !!bool tags are decoded according to the tag
+and scalars tagged !!binary are base64-decoded into TypeBytes. Other tags are
+not supported and result in an error.
+
+Anchors and aliases are supported. Each alias is expanded into a copy of
+the anchored node. To protect against exponential expansion of nested aliases
+("billion laughs" attack) the total number of nodes created by alias expansion
+is limited (see Decoder.SetMaxAliasNodes).
+
+# Encoding
+
+TypeEmpty is encoded as null, TypeInt and TypeFloat64 as numbers, TypeString as
+a string (quoted if it would otherwise be decoded as a different type), TypeBytes
+as a !!binary scalar, TypeValueList as a sequence and TypeKeyValueList as
+a mapping. The array types are encoded as sequences, which are decoded back as
+TypeValueList. Encoding and decoding round trips all other Variant types exactly.
+*/
+package yaml
+
+import (
+	"bytes"
+	"io"
+
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/tigrannajaryan/govariant/variant"
+)
+
+// Unmarshal decodes the first YAML document in data. An empty input is decoded
+// as TypeEmpty.
+func Unmarshal(data []byte) (variant.Variant, error) {
+	v, err := NewDecoder(bytes.NewReader(data)).Decode()
+	if err == io.EOF {
+		return variant.NewEmpty(), nil
+	}
+	return v, err
+}
+
+// Marshal encodes v as a YAML document.
+func Marshal(v variant.Variant) ([]byte, error) {
+	var buf bytes.Buffer
+	e := NewEncoder(&buf)
+	if err := e.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := e.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// An Encoder writes Variants as a stream of YAML documents.
+type Encoder struct {
+	e *yamlv3.Encoder
+}
+
+// NewEncoder returns a new Encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{e: yamlv3.NewEncoder(w)}
+}
+
+// SetIndent changes the number of spaces used for indentation. The default is 4.
+func (e *Encoder) SetIndent(spaces int) {
+	e.e.SetIndent(spaces)
+}
+
+// Encode writes v as the next YAML document in the stream.
+func (e *Encoder) Encode(v variant.Variant) error {
+	return e.e.Encode(encodeNode(v))
+}
+
+// Close flushes any remaining data. Must be called after the last Encode.
+func (e *Encoder) Close() error {
+	return e.e.Close()
+}
@@ -0,0 +1,238 @@
+package yaml
+
+import (
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tigrannajaryan/govariant/variant"
+)
+
+func TestUnmarshalScalars(t *testing.T) {
+	tests := []struct {
+		yaml string
+		str  string
+		typ  variant.Type
+	}{
+		{``, ``, variant.TypeEmpty},
+		{`~`, ``, variant.TypeEmpty},
+		{`NULL`, ``, variant.TypeEmpty},
+		{`True`, `"true"`, variant.TypeString},
+		{`FALSE`, `"false"`, variant.TypeString},
+		{`yes`, `"yes"`, variant.TypeString},
+		{`123`, `123`, variant.TypeInt},
+		{`-0`, `0`, variant.TypeInt},
+		{`+12`, `12`, variant.TypeInt},
+		{`0o17`, `15`, variant.TypeInt},
+		{`0x1F`, `31`, variant.TypeInt},
+		{`0b11`, `"0b11"`, variant.TypeString},
+		{`1_000`, `"1_000"`, variant.TypeString},
+		{`012`, `12`, variant.TypeInt},
+		{`99999999999999999999`, `1e+20`, variant.TypeFloat64},
+		{`0x1FFFFFFFFFFFFFFFF`, `"0x1FFFFFFFFFFFFFFFF"`, variant.TypeString},
+		{`1.5`, `1.5`, variant.TypeFloat64},
+		{`.5`, `0.5`, variant.TypeFloat64},
+		{`1.`, `1`, variant.TypeFloat64},
+		{`-1e3`, `-1000`, variant.TypeFloat64},
+		{`1e400`, `+Inf`, variant.TypeFloat64},
+		{`.Inf`, `+Inf`, variant.TypeFloat64},
+		{`-.INF`, `-Inf`, variant.TypeFloat64},
+		{`.NaN`, `NaN`, variant.TypeFloat64},
+		{`.nAn`, `".nAn"`, variant.TypeString},
+		{`"123"`, `"123"`, variant.TypeString},
+		{`'null'`, `"null"`, variant.TypeString},
+		{"|\n  12\n", `"12\n"`, variant.TypeString},
+		{`<<`, `"<<"`, variant.TypeString},
+		{`!!str 123`, `"123"`, variant.TypeString},
+		{`!!int "0x10"`, `16`, variant.TypeInt},
+		{`!!float 1`, `1`, variant.TypeFloat64},
+		{`!!null ""`, ``, variant.TypeEmpty},
+		{`!!bool True`, `"true"`, variant.TypeString},
+		{`!!binary AQID`, `0x010203`, variant.TypeBytes},
+		{"!!binary |\n  AQID\n  BA==\n", `0x01020304`, variant.TypeBytes},
+	}
+
+	for _, test := range tests {
+		t.Run(test.yaml, func(t *testing.T) {
+			v, err := Unmarshal([]byte(test.yaml))
+			require.NoError(t, err)
+			assert.EqualValues(t, test.typ, v.Type())
+			assert.EqualValues(t, test.str, v.String())
+		})
+	}
+}
+
+func TestUnmarshalCollections(t *testing.T) {
+	doc := `
+z: 1
+a:
+  - x
+  - [1, 2.5]
+  - {k: v}
+m:
+  nested: ~
+  "quoted key": 'str'
+`
+	v, err := Unmarshal([]byte(doc))
+	require.NoError(t, err)
+	assert.EqualValues(t, `{"z":1,"a":["x",[1,2.5],{"k":"v"}],"m":{"nested":,"quoted key":"str"}}`, v.String())
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	invalid := []string{
+		"a: [",
+		"!!int abc",
+		"!!float abc",
+		"!!null abc",
+		"!!bool yes",
+		"!!binary ???",
+		"!custom x",
+		"!!map [1]",
+		"!!seq {a: 1}",
+		"? [a]\n: b",
+		"a: 1\na: 2",
+		"a: *unknown",
+		"&a [*a]",
+	}
+	for _, s := range invalid {
+		_, err := Unmarshal([]byte(s))
+		assert.Error(t, err, s)
+	}
+}
+
+func TestUnmarshalAliases(t *testing.T) {
+	doc := `
+base: &b
+  x: 1
+  y: [&s str]
+copy: *b
+name: *s
+*s : key from alias
+`
+	v, err := Unmarshal([]byte(doc))
+	require.NoError(t, err)
+	assert.EqualValues(t, `{"base":{"x":1,"y":["str"]},"copy":{"x":1,"y":["str"]},"name":"str","str":"key from alias"}`, v.String())
+
+	// Aliases are expanded into copies.
+	list := v.KeyValueList()
+	list[0].Value.KeyValueAt(0).Value = variant.NewInt(2)
+	assert.EqualValues(t, `{"x":1,"y":["str"]}`, list[1].Value.String())
+}
+
+func TestUnmarshalBillionLaughs(t *testing.T) {
+	doc := `
+a: &a ["lol","lol","lol","lol","lol","lol","lol","lol","lol"]
+b: &b [*a,*a,*a,*a,*a,*a,*a,*a,*a]
+c: &c [*b,*b,*b,*b,*b,*b,*b,*b,*b]
+d: &d [*c,*c,*c,*c,*c,*c,*c,*c,*c]
+e: &e [*d,*d,*d,*d,*d,*d,*d,*d,*d]
+f: &f [*e,*e,*e,*e,*e,*e,*e,*e,*e]
+g: &g [*f,*f,*f,*f,*f,*f,*f,*f,*f]
+h: &h [*g,*g,*g,*g,*g,*g,*g,*g,*g]
+i: &i [*h,*h,*h,*h,*h,*h,*h,*h,*h]
+`
+	_, err := Unmarshal([]byte(doc))
+	assert.Equal(t, errAliasLimit, err)
+
+	d := NewDecoder(strings.NewReader("a: &a [1, 2]\nb: [*a, *a]"))
+	d.SetMaxAliasNodes(5)
+	_, err = d.Decode()
+	assert.Equal(t, errAliasLimit, err)
+
+	d = NewDecoder(strings.NewReader("a: &a [1, 2]\nb: [*a, *a]"))
+	d.SetMaxAliasNodes(6)
+	v, err := d.Decode()
+	require.NoError(t, err)
+	assert.EqualValues(t, `{"a":[1,2],"b":[[1,2],[1,2]]}`, v.String())
+}
+
+func TestDecoderStream(t *testing.T) {
+	d := NewDecoder(strings.NewReader("1\n---\na: b\n---\n"))
+
+	v, err := d.Decode()
+	require.NoError(t, err)
+	assert.EqualValues(t, `1`, v.String())
+
+	v, err = d.Decode()
+	require.NoError(t, err)
+	assert.EqualValues(t, `{"a":"b"}`, v.String())
+
+	v, err = d.Decode()
+	require.NoError(t, err)
+	assert.EqualValues(t, variant.TypeEmpty, v.Type())
+
+	_, err = d.Decode()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestMarshal(t *testing.T) {
+	v := variant.NewKeyValueList(
+		[]variant.KeyValue{
+			{Key: "int", Value: variant.NewInt(1)},
+			{Key: "float", Value: variant.NewFloat64(2)},
+			{Key: "str", Value: variant.NewString("abc")},
+			{Key: "numstr", Value: variant.NewString("0x10")},
+			{Key: "empty", Value: variant.NewEmpty()},
+			{Key: "bytes", Value: variant.NewBytes([]byte{1, 2, 3})},
+			{Key: "list", Value: variant.NewValueList([]variant.Variant{variant.NewString("a"), variant.NewValueList(nil)})},
+			{Key: "floats", Value: variant.NewFloat64Array([]float64{0.5, 1})},
+			{Key: "null", Value: variant.NewKeyValueList(nil)},
+		},
+	)
+	b, err := Marshal(v)
+	require.NoError(t, err)
+	assert.EqualValues(t, `int: 1
+float: 2.0
+str: abc
+numstr: "0x10"
+empty: null
+bytes: !!binary AQID
+list:
+    - a
+    - []
+floats:
+    - 0.5
+    - 1.0
+"null": {}
+`, string(b))
+}
+
+func TestRoundTrip(t *testing.T) {
+	strs := []string{"", "true", "True", "null", "~", "1", "1.5", ".inf", "a\nb ", " lead", "trail ", "- x", "a: b", "#c", "é"}
+	var list []variant.Variant
+	for _, s := range strs {
+		list = append(list, variant.NewString(s))
+	}
+	list = append(list,
+		variant.NewEmpty(),
+		variant.NewInt(math.MinInt32),
+		variant.NewFloat64(1e21),
+		variant.NewFloat64(-0.5),
+		variant.NewFloat64(math.Inf(1)),
+		variant.NewFloat64(math.Inf(-1)),
+		variant.NewBytes(nil),
+		variant.NewBytes([]byte{0xFF}),
+		variant.NewKeyValueList([]variant.KeyValue{{Key: "1", Value: variant.NewValueList(nil)}}),
+	)
+	v := variant.NewValueList(list)
+
+	b, err := Marshal(v)
+	require.NoError(t, err)
+	r, err := Unmarshal(b)
+	require.NoError(t, err)
+	assert.EqualValues(t, v.String(), r.String())
+
+	nan := variant.NewFloat64(math.NaN())
+	b, err = Marshal(nan)
+	require.NoError(t, err)
+	r, err = Unmarshal(b)
+	require.NoError(t, err)
+	assert.True(t, math.IsNaN(r.Float64Val()))
+
+	_, err = Marshal(variant.NewString("\xff"))
+	assert.Error(t, err)
+}